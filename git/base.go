@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// BaseChange describes a file changed since the merge-base with a base branch,
+// together with the layers (commits, index, working tree) the change lives in
+type BaseChange struct {
+	FileInfo
+	Committed bool // changed by a commit between the merge-base and HEAD
+	Staged    bool // changed in the index
+	Unstaged  bool // changed in the working tree (including untracked files)
+}
+
+// GetMergeBase returns the merge-base of HEAD and the base branch
+func GetMergeBase(base string, repoRoot string) (string, error) {
+	cmd := exec.Command("git", "merge-base", "HEAD", base)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no merge-base between HEAD and %s", base)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetBaseChanges returns every file that differs between the merge-base and the working tree
+func GetBaseChanges(mergeBase string, repoRoot string) ([]BaseChange, error) {
+	files, err := GetRevisionChangedFiles(RevisionRange{From: mergeBase}, repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	committed, err := listPaths(repoRoot, "diff", "--name-only", "--no-renames", "-z", mergeBase, "HEAD", "--")
+	if err != nil {
+		return nil, err
+	}
+	staged, err := listPaths(repoRoot, "diff", "--cached", "--name-only", "--no-renames", "-z", "--")
+	if err != nil {
+		return nil, err
+	}
+	unstaged, err := listPaths(repoRoot, "diff", "--name-only", "--no-renames", "-z", "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := listPaths(repoRoot, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]BaseChange, 0, len(files)+len(untracked))
+	for _, f := range files {
+		changes = append(changes, BaseChange{
+			FileInfo:  f,
+			Committed: committed[f.Path],
+			Staged:    staged[f.Path],
+			Unstaged:  unstaged[f.Path],
+		})
+	}
+	for path := range untracked {
+		changes = append(changes, BaseChange{
+			FileInfo: FileInfo{Path: path, ChangeStatus: "untracked"},
+			Unstaged: true,
+		})
+	}
+	return changes, nil
+}
+
+// listPaths runs a git command printing NUL-separated paths and returns them as a set
+func listPaths(repoRoot string, args ...string) (map[string]bool, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute git %s: %w", args[0], err)
+	}
+
+	paths := make(map[string]bool)
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path != "" {
+			paths[path] = true
+		}
+	}
+	return paths, nil
+}
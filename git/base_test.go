@@ -0,0 +1,82 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestGetBaseChanges_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("committed.txt", "one\n")
+	writeFile("staged.txt", "one\n")
+	writeFile("main-only.txt", "one\n")
+	runGit("add", ".")
+	runGit("commit", "-m", "initial")
+	forkPoint := runGit("rev-parse", "HEAD")
+
+	// The base branch moves on after the feature branch forked
+	runGit("checkout", "-b", "feature")
+	runGit("checkout", "main")
+	writeFile("main-only.txt", "two\n")
+	runGit("commit", "-am", "change on main")
+	runGit("checkout", "feature")
+
+	writeFile("committed.txt", "two\n")
+	runGit("commit", "-am", "change on feature")
+	writeFile("committed.txt", "three\n")
+	writeFile("staged.txt", "two\n")
+	runGit("add", "staged.txt")
+	writeFile("untracked.txt", "new\n")
+
+	mergeBase, err := GetMergeBase("main", tmpDir)
+	if err != nil {
+		t.Fatalf("GetMergeBase failed: %v", err)
+	}
+	if mergeBase != forkPoint {
+		t.Errorf("GetMergeBase() = %s, want the fork point %s", mergeBase, forkPoint)
+	}
+	if _, err := GetMergeBase("no-such-branch", tmpDir); err == nil {
+		t.Error("GetMergeBase of an unknown branch should fail")
+	}
+
+	changes, err := GetBaseChanges(mergeBase, tmpDir)
+	if err != nil {
+		t.Fatalf("GetBaseChanges failed: %v", err)
+	}
+	got := make(map[string]BaseChange)
+	for _, change := range changes {
+		got[change.Path] = change
+	}
+
+	want := map[string]BaseChange{
+		"committed.txt": {FileInfo: FileInfo{ChangeStatus: "modified"}, Committed: true, Unstaged: true},
+		"staged.txt":    {FileInfo: FileInfo{ChangeStatus: "modified"}, Staged: true},
+		"untracked.txt": {FileInfo: FileInfo{ChangeStatus: "untracked"}, Unstaged: true},
+	}
+	if len(got) != len(want) {
+		t.Errorf("GetBaseChanges() returned %d files, want %d: %+v", len(got), len(want), changes)
+	}
+	for path, w := range want {
+		g, ok := got[path]
+		if !ok {
+			t.Errorf("%s is missing from the base changes", path)
+			continue
+		}
+		if g.ChangeStatus != w.ChangeStatus || g.Committed != w.Committed || g.Staged != w.Staged || g.Unstaged != w.Unstaged {
+			t.Errorf("%s = %+v, want %+v", path, g, w)
+		}
+	}
+	if _, ok := got["main-only.txt"]; ok {
+		t.Error("a change made only on the base branch must not be listed")
+	}
+}
@@ -0,0 +1,109 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlameLine is one line of `git blame` output
+type BlameLine struct {
+	Hash     string
+	Author   string
+	Time     time.Time
+	Summary  string
+	OrigPath string // path of the file in Hash
+	OrigLine int    // line number in Hash
+	Line     int    // line number in the blamed revision
+	Content  string
+	Boundary bool // Hash is a root commit (or the boundary of the blamed range)
+}
+
+// IsUncommitted reports whether the line only exists in the working tree
+func (l BlameLine) IsUncommitted() bool {
+	return strings.Trim(l.Hash, "0") == ""
+}
+
+// GetBlame blames filePath at rev, or the working tree file when rev is empty
+func GetBlame(repoRoot, rev, filePath string) ([]BlameLine, error) {
+	args := []string{"-c", "core.quotepath=false", "blame", "--porcelain"}
+	if rev != "" {
+		args = append(args, rev)
+	}
+	args = append(args, "--", filePath)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s: %s", filePath, strings.TrimSpace(string(output)))
+	}
+	return parseBlamePorcelain(string(output)), nil
+}
+
+// parseBlamePorcelain parses `git blame --porcelain` output.
+// Commit details are only printed the first time a commit appears.
+func parseBlamePorcelain(output string) []BlameLine {
+	type commitInfo struct {
+		author   string
+		time     time.Time
+		summary  string
+		path     string
+		boundary bool
+	}
+	commits := make(map[string]*commitInfo)
+
+	var lines []BlameLine
+	var current BlameLine
+	var info *commitInfo
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "\t") && info != nil {
+			// Content line ends the entry
+			current.Content = line[1:]
+			current.Author = info.author
+			current.Time = info.time
+			current.Summary = info.summary
+			current.OrigPath = info.path
+			current.Boundary = info.boundary
+			lines = append(lines, current)
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		if (len(key) == 40 || len(key) == 64) && strings.Trim(key, "0123456789abcdef") == "" {
+			// Header: <hash> <orig line> <final line> [<group size>]
+			fields := strings.Fields(value)
+			if len(fields) < 2 {
+				continue
+			}
+			current = BlameLine{Hash: key}
+			current.OrigLine, _ = strconv.Atoi(fields[0])
+			current.Line, _ = strconv.Atoi(fields[1])
+			if commits[key] == nil {
+				commits[key] = &commitInfo{}
+			}
+			info = commits[key]
+			continue
+		}
+		if info == nil {
+			continue
+		}
+
+		switch key {
+		case "author":
+			info.author = value
+		case "author-time":
+			if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+				info.time = time.Unix(sec, 0)
+			}
+		case "summary":
+			info.summary = value
+		case "filename":
+			info.path = value
+		case "boundary":
+			info.boundary = true
+		}
+	}
+	return lines
+}
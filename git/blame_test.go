@@ -0,0 +1,63 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestGetBlame_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	writeFile := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, "file.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("one\ntwo\n")
+	runGit("add", "file.txt")
+	runGit("commit", "-m", "initial")
+	first := runGit("rev-parse", "HEAD")
+
+	writeFile("zero\none\nTWO\n")
+	runGit("commit", "-am", "change two")
+	second := runGit("rev-parse", "HEAD")
+
+	writeFile("zero\none\nTWO\nthree\n")
+
+	lines, err := GetBlame(tmpDir, "", "file.txt")
+	if err != nil {
+		t.Fatalf("GetBlame failed: %v", err)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %+v", lines)
+	}
+	if lines[0].Hash != second || lines[0].Content != "zero" || lines[0].Author != "Test User" || lines[0].Summary != "change two" {
+		t.Errorf("unexpected line 1: %+v", lines[0])
+	}
+	if lines[1].Hash != first || lines[1].Line != 2 || lines[1].OrigLine != 1 || !lines[1].Boundary {
+		t.Errorf("unexpected line 2: %+v", lines[1])
+	}
+	if lines[2].Hash != second || lines[2].OrigPath != "file.txt" || lines[2].OrigLine != 3 || lines[2].Boundary {
+		t.Errorf("unexpected line 3: %+v", lines[2])
+	}
+	if !lines[3].IsUncommitted() || lines[3].Content != "three" {
+		t.Errorf("expected line 4 to be uncommitted, got %+v", lines[3])
+	}
+
+	// Blaming the parent shows the line before it was changed
+	lines, err = GetBlame(tmpDir, second+"^", "file.txt")
+	if err != nil {
+		t.Fatalf("GetBlame at parent failed: %v", err)
+	}
+	if len(lines) != 2 || lines[1].Content != "two" || lines[1].Hash != first {
+		t.Errorf("unexpected blame at parent: %+v", lines)
+	}
+
+	if _, err := GetBlame(tmpDir, "", "missing.txt"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
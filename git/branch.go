@@ -0,0 +1,113 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Branch is a local branch with its upstream tracking information
+type Branch struct {
+	Name         string
+	Hash         string
+	Subject      string // subject of the tip commit
+	Current      bool   // checked out in this worktree
+	Upstream     string // e.g. "origin/main", empty if not tracking
+	UpstreamGone bool   // the upstream is configured but its ref no longer exists
+	Ahead        int    // commits on the branch that are not on the upstream
+	Behind       int    // commits on the upstream that are not on the branch
+}
+
+// ListBranches returns the local branches with ahead/behind counts against
+// their upstream, computed from local refs only (nothing is fetched)
+func ListBranches(repoRoot string) ([]Branch, error) {
+	return readBranches(repoRoot, "refs/heads")
+}
+
+// readBranches reads the branches matching the for-each-ref patterns
+func readBranches(repoRoot string, patterns ...string) ([]Branch, error) {
+	args := append([]string{"for-each-ref",
+		"--format=%(HEAD)%1f%(refname:short)%1f%(objectname)%1f%(upstream:short)%1f%(subject)"},
+		patterns...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute git for-each-ref: %w", err)
+	}
+
+	var branches []Branch
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, "\x1f", 5)
+		if len(fields) < 5 {
+			continue
+		}
+		branch := Branch{
+			Current:  fields[0] == "*",
+			Name:     fields[1],
+			Hash:     fields[2],
+			Upstream: fields[3],
+			Subject:  fields[4],
+		}
+		if branch.Upstream != "" {
+			ahead, behind, err := AheadBehind(repoRoot, "refs/heads/"+branch.Name, branch.Upstream)
+			if err != nil {
+				branch.UpstreamGone = true
+			} else {
+				branch.Ahead, branch.Behind = ahead, behind
+			}
+		}
+		branches = append(branches, branch)
+	}
+	return branches, nil
+}
+
+// AheadBehind counts the commits reachable from local but not upstream (ahead)
+// and from upstream but not local (behind)
+func AheadBehind(repoRoot, local, upstream string) (int, int, error) {
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", local+"..."+upstream, "--")
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare %s with %s: %w", local, upstream, err)
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected git rev-list output: %q", output)
+	}
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// CheckoutBranch switches the worktree to branch
+func CheckoutBranch(repoRoot, branch string) error {
+	return runGit(repoRoot, "checkout", branch, "--")
+}
+
+// CreateBranch creates branch at startPoint (a commit or "HEAD") without switching to it
+func CreateBranch(repoRoot, branch, startPoint string) error {
+	return runGit(repoRoot, "branch", "--", branch, startPoint)
+}
+
+// RenameBranch renames a local branch
+func RenameBranch(repoRoot, oldName, newName string) error {
+	return runGit(repoRoot, "branch", "-m", "--", oldName, newName)
+}
+
+// DeleteBranch deletes a local branch. Without force, git refuses to delete
+// a branch that is not merged.
+func DeleteBranch(repoRoot, branch string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	return runGit(repoRoot, "branch", flag, "--", branch)
+}
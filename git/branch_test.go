@@ -0,0 +1,60 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestListBranches_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+
+	runGit("commit", "--allow-empty", "-m", "initial")
+
+	// "upstream" plays the remote-tracking branch; it is one commit ahead
+	if err := CreateBranch(tmpDir, "upstream", "HEAD"); err != nil {
+		t.Fatalf("CreateBranch failed: %v", err)
+	}
+	if err := CheckoutBranch(tmpDir, "upstream"); err != nil {
+		t.Fatalf("CheckoutBranch failed: %v", err)
+	}
+	runGit("commit", "--allow-empty", "-m", "upstream work")
+	if err := CheckoutBranch(tmpDir, "main"); err != nil {
+		t.Fatalf("CheckoutBranch failed: %v", err)
+	}
+	runGit("commit", "--allow-empty", "-m", "local 1")
+	runGit("commit", "--allow-empty", "-m", "local 2")
+	runGit("branch", "--set-upstream-to=upstream")
+
+	branches, err := ListBranches(tmpDir)
+	if err != nil {
+		t.Fatalf("ListBranches failed: %v", err)
+	}
+	if len(branches) != 2 {
+		t.Fatalf("expected 2 branches, got %+v", branches)
+	}
+	main := branches[0]
+	if main.Name != "main" || !main.Current || main.Upstream != "upstream" || main.Ahead != 2 || main.Behind != 1 || main.Subject != "local 2" {
+		t.Errorf("unexpected main branch: %+v", main)
+	}
+	if branches[1].Current || branches[1].Upstream != "" {
+		t.Errorf("unexpected upstream branch: %+v", branches[1])
+	}
+
+	// Rename and delete
+	if err := RenameBranch(tmpDir, "upstream", "other"); err != nil {
+		t.Fatalf("RenameBranch failed: %v", err)
+	}
+	if err := DeleteBranch(tmpDir, "other", false); err == nil {
+		t.Error("expected deleting an unmerged branch to fail without force")
+	}
+	if err := DeleteBranch(tmpDir, "other", true); err != nil {
+		t.Fatalf("DeleteBranch failed: %v", err)
+	}
+
+	// The upstream of main is gone now
+	branches, err = ListBranches(tmpDir)
+	if err != nil || len(branches) != 1 || !branches[0].UpstreamGone {
+		t.Errorf("expected main with a gone upstream, got %+v (err: %v)", branches, err)
+	}
+}
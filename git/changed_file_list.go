@@ -4,13 +4,33 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
 // FileInfo represents a file with its status
 type FileInfo struct {
 	Path         string
-	ChangeStatus string // "added", "deleted", "modified", "untracked"
+	ChangeStatus string // "added", "deleted", "modified", "renamed", "copied", "type-changed", "conflicted", "untracked"
+
+	OrigPath   string          // original path for renames and copies
+	Similarity int             // similarity score (0-100) for renames and copies
+	Submodule  *SubmoduleState // nil if the path is not a submodule
+	Conflict   string          // unmerged status code ("UU", "AA", "DU", ...) for conflicted files
+	OldMode    string          // file mode before the change (e.g. "100644")
+	NewMode    string          // file mode after the change (e.g. "100755")
+}
+
+// SubmoduleState describes the state of a changed submodule
+type SubmoduleState struct {
+	CommitChanged bool // the checked out commit differs
+	Modified      bool // the submodule has tracked modifications
+	Untracked     bool // the submodule has untracked files
+}
+
+// HasModeChange reports whether the file mode changed
+func (f FileInfo) HasModeChange() bool {
+	return f.OldMode != "" && f.NewMode != "" && f.OldMode != f.NewMode
 }
 
 // FindGitRoot searches for the .git directory by traversing up from the current directory
@@ -43,99 +63,179 @@ func GetChangedFiles(repoPath string) ([]FileInfo, []FileInfo, []FileInfo, error
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	// Run git status --porcelain to get both staged and unstaged files
-	// -c core.quotepath=false prevents escaping of multibyte filenames
-	cmd := exec.Command("git", "-c", "core.quotepath=false", "status", "--porcelain")
+	// -z keeps file names verbatim (no quoting, no " -> " separators)
+	// --untracked-files=all lists files inside untracked directories individually
+	cmd := exec.Command("git", "status", "--porcelain=v2", "-z", "--untracked-files=all")
 	cmd.Dir = gitRoot
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
+	stagedFiles, modifiedFiles, untrackedFiles := parsePorcelainV2(string(output))
+	return stagedFiles, modifiedFiles, untrackedFiles, nil
+}
+
+// parsePorcelainV2 parses `git status --porcelain=v2 -z` output into
+// staged, unstaged and untracked files
+func parsePorcelainV2(output string) ([]FileInfo, []FileInfo, []FileInfo) {
 	var stagedFiles []FileInfo
 	var modifiedFiles []FileInfo
 	var untrackedFiles []FileInfo
 
-	// Parse output (remove only empty lines, without using TrimSpace)
-	lines := strings.Split(string(output), "\n")
-	// Remove empty lines
-	var filteredLines []string
-	for _, line := range lines {
-		if len(line) > 0 {
-			filteredLines = append(filteredLines, line)
-		}
-	}
-	lines = filteredLines
-	for _, line := range lines {
-		if len(line) < 3 {
+	records := strings.Split(output, "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if len(record) < 2 {
 			continue
 		}
 
-		// git status --porcelain format: XY filename
-		// X = index status, Y = worktree status
-		indexStatus := line[0]
-		worktreeStatus := line[1]
-		filename := strings.TrimSpace(line[2:]) // Filename starts after the 2nd character
-
-		// Handle rename case (R  old -> new)
-		if indexStatus == 'R' {
-			// Split by " -> " to get old and new filenames
-			parts := strings.Split(filename, " -> ")
-			oldFile := parts[0]
-			newFile := parts[1]
-			// Treat old file as deleted, new file as added
-			stagedFiles = append(stagedFiles, FileInfo{Path: oldFile, ChangeStatus: "deleted"})
-			stagedFiles = append(stagedFiles, FileInfo{Path: newFile, ChangeStatus: "added"})
-		} else if worktreeStatus == 'R' {
-			// Unstaged rename
-			parts := strings.Split(filename, " -> ")
-			oldFile := parts[0]
-			newFile := parts[1]
-			modifiedFiles = append(modifiedFiles, FileInfo{Path: oldFile, ChangeStatus: "deleted"})
-			modifiedFiles = append(modifiedFiles, FileInfo{Path: newFile, ChangeStatus: "added"})
-		} else if indexStatus == '?' && worktreeStatus == '?' {
-			// Untracked file
-			// Check if it is a directory
-			fullPath := filepath.Join(gitRoot, filename)
-			fileInfo, statErr := os.Stat(fullPath)
-			if statErr == nil && fileInfo.IsDir() {
-				// For directories, use git ls-files to quickly list untracked files
-				lsCmd := exec.Command("git", "-c", "core.quotepath=false", "ls-files", "--others", "--exclude-standard", filename+"/")
-				lsCmd.Dir = gitRoot
-				lsOutput, lsErr := lsCmd.Output()
-				if lsErr == nil {
-					for _, f := range strings.Split(strings.TrimSpace(string(lsOutput)), "\n") {
-						if f != "" {
-							untrackedFiles = append(untrackedFiles, FileInfo{Path: f, ChangeStatus: "untracked"})
-						}
-					}
-				}
-			} else {
-				// For files, add as-is
-				untrackedFiles = append(untrackedFiles, FileInfo{Path: filename, ChangeStatus: "untracked"})
+		switch record[0] {
+		case '1':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			fields := strings.SplitN(record, " ", 9)
+			if len(fields) < 9 {
+				continue
 			}
-		} else {
-			// Add appropriate info based on status
-			if indexStatus == 'A' {
-				stagedFiles = append(stagedFiles, FileInfo{Path: filename, ChangeStatus: "added"})
-			} else if indexStatus == 'D' {
-				stagedFiles = append(stagedFiles, FileInfo{Path: filename, ChangeStatus: "deleted"})
-			} else if indexStatus == 'M' {
-				stagedFiles = append(stagedFiles, FileInfo{Path: filename, ChangeStatus: "modified"})
-			} else if indexStatus != ' ' && indexStatus != '?' {
-				stagedFiles = append(stagedFiles, FileInfo{Path: filename, ChangeStatus: "modified"})
+			entry := porcelainEntry{
+				xy:        fields[1],
+				submodule: parseSubmoduleState(fields[2]),
+				modeHead:  fields[3],
+				modeIndex: fields[4],
+				modeWork:  fields[5],
+				path:      fields[8],
 			}
-
-			// Unstaged changes
-			if worktreeStatus == 'D' {
-				modifiedFiles = append(modifiedFiles, FileInfo{Path: filename, ChangeStatus: "deleted"})
-			} else if worktreeStatus == 'M' {
-				modifiedFiles = append(modifiedFiles, FileInfo{Path: filename, ChangeStatus: "modified"})
-			} else if worktreeStatus != ' ' && worktreeStatus != '?' {
-				modifiedFiles = append(modifiedFiles, FileInfo{Path: filename, ChangeStatus: "modified"})
+			if staged, ok := entry.indexChange(); ok {
+				stagedFiles = append(stagedFiles, staged)
+			}
+			if modified, ok := entry.worktreeChange(); ok {
+				modifiedFiles = append(modifiedFiles, modified)
+			}
+		case '2':
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path>\0<origPath>
+			fields := strings.SplitN(record, " ", 10)
+			if len(fields) < 10 || i+1 >= len(records) {
+				continue
+			}
+			i++
+			entry := porcelainEntry{
+				xy:        fields[1],
+				submodule: parseSubmoduleState(fields[2]),
+				modeHead:  fields[3],
+				modeIndex: fields[4],
+				modeWork:  fields[5],
+				path:      fields[9],
+				origPath:  records[i],
+			}
+			if len(fields[8]) > 1 {
+				entry.similarity, _ = strconv.Atoi(fields[8][1:])
+			}
+			if staged, ok := entry.indexChange(); ok {
+				stagedFiles = append(stagedFiles, staged)
+			}
+			if modified, ok := entry.worktreeChange(); ok {
+				modifiedFiles = append(modifiedFiles, modified)
+			}
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) < 11 {
+				continue
 			}
+			modifiedFiles = append(modifiedFiles, FileInfo{
+				Path:         fields[10],
+				ChangeStatus: "conflicted",
+				Conflict:     fields[1],
+				Submodule:    parseSubmoduleState(fields[2]),
+			})
+		case '?':
+			untrackedFiles = append(untrackedFiles, FileInfo{Path: record[2:], ChangeStatus: "untracked"})
 		}
+		// '#' headers and '!' ignored entries are skipped
 	}
 
-	return stagedFiles, modifiedFiles, untrackedFiles, nil
+	return stagedFiles, modifiedFiles, untrackedFiles
+}
+
+// porcelainEntry holds the fields of a changed (ordinary or renamed/copied) status entry
+type porcelainEntry struct {
+	xy         string
+	submodule  *SubmoduleState
+	modeHead   string
+	modeIndex  string
+	modeWork   string
+	path       string
+	origPath   string
+	similarity int
+}
+
+// indexChange returns the staged side (HEAD -> index) of the entry
+func (e porcelainEntry) indexChange() (FileInfo, bool) {
+	info := FileInfo{Path: e.path, Submodule: e.submodule}
+	switch e.xy[0] {
+	case 'A':
+		info.ChangeStatus = "added"
+	case 'D':
+		info.ChangeStatus = "deleted"
+	case 'M':
+		info.ChangeStatus = "modified"
+	case 'T':
+		info.ChangeStatus = "type-changed"
+	case 'R', 'C':
+		info.ChangeStatus = "renamed"
+		if e.xy[0] == 'C' {
+			info.ChangeStatus = "copied"
+		}
+		info.OrigPath = e.origPath
+		info.Similarity = e.similarity
+	default:
+		return FileInfo{}, false
+	}
+	if e.modeHead != "000000" && e.modeIndex != "000000" {
+		info.OldMode = e.modeHead
+		info.NewMode = e.modeIndex
+	}
+	return info, true
+}
+
+// worktreeChange returns the unstaged side (index -> working tree) of the entry
+func (e porcelainEntry) worktreeChange() (FileInfo, bool) {
+	info := FileInfo{Path: e.path, Submodule: e.submodule}
+	switch e.xy[1] {
+	case 'A':
+		// Intent-to-add entries
+		info.ChangeStatus = "added"
+	case 'D':
+		info.ChangeStatus = "deleted"
+	case 'M':
+		info.ChangeStatus = "modified"
+	case 'T':
+		info.ChangeStatus = "type-changed"
+	case 'R', 'C':
+		info.ChangeStatus = "renamed"
+		if e.xy[1] == 'C' {
+			info.ChangeStatus = "copied"
+		}
+		info.OrigPath = e.origPath
+		info.Similarity = e.similarity
+	default:
+		return FileInfo{}, false
+	}
+	if e.modeIndex != "000000" && e.modeWork != "000000" {
+		info.OldMode = e.modeIndex
+		info.NewMode = e.modeWork
+	}
+	return info, true
+}
+
+// parseSubmoduleState parses the <sub> field ("N..." or "S<c><m><u>")
+func parseSubmoduleState(field string) *SubmoduleState {
+	if len(field) != 4 || field[0] != 'S' {
+		return nil
+	}
+	return &SubmoduleState{
+		CommitChanged: field[1] == 'C',
+		Modified:      field[2] == 'M',
+		Untracked:     field[3] == 'U',
+	}
 }
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestParsePorcelainV2(t *testing.T) {
+	output := "# branch.oid 0123456789abcdef\x00" +
+		"1 M. N... 100644 100644 100644 aaaa bbbb staged.txt\x00" +
+		"1 .M N... 100644 100644 100755 aaaa aaaa script.sh\x00" +
+		"1 MM N... 100644 100644 100644 aaaa bbbb both.txt\x00" +
+		"2 R. N... 100644 100644 100644 aaaa aaaa R87 a -> b.txt\x00old name.txt\x00" +
+		"1 T. N... 100644 120000 120000 aaaa cccc link\x00" +
+		"1 .M SC.U 160000 160000 160000 dddd dddd vendor/lib\x00" +
+		"u UU N... 100644 100644 100644 100644 1111 2222 3333 conflict.txt\x00" +
+		"?  leading space.txt\x00" +
+		"! ignored.log\x00"
+
+	staged, modified, untracked := parsePorcelainV2(output)
+
+	expectedStaged := []FileInfo{
+		{Path: "staged.txt", ChangeStatus: "modified", OldMode: "100644", NewMode: "100644"},
+		{Path: "both.txt", ChangeStatus: "modified", OldMode: "100644", NewMode: "100644"},
+		{Path: "a -> b.txt", ChangeStatus: "renamed", OrigPath: "old name.txt", Similarity: 87, OldMode: "100644", NewMode: "100644"},
+		{Path: "link", ChangeStatus: "type-changed", OldMode: "100644", NewMode: "120000"},
+	}
+	expectedModified := []FileInfo{
+		{Path: "script.sh", ChangeStatus: "modified", OldMode: "100644", NewMode: "100755"},
+		{Path: "both.txt", ChangeStatus: "modified", OldMode: "100644", NewMode: "100644"},
+		{Path: "vendor/lib", ChangeStatus: "modified", OldMode: "160000", NewMode: "160000",
+			Submodule: &SubmoduleState{CommitChanged: true, Untracked: true}},
+		{Path: "conflict.txt", ChangeStatus: "conflicted", Conflict: "UU"},
+	}
+	expectedUntracked := []FileInfo{
+		{Path: " leading space.txt", ChangeStatus: "untracked"},
+	}
+
+	if !reflect.DeepEqual(staged, expectedStaged) {
+		t.Errorf("staged = %+v, want %+v", staged, expectedStaged)
+	}
+	if !reflect.DeepEqual(modified, expectedModified) {
+		t.Errorf("modified = %+v, want %+v", modified, expectedModified)
+	}
+	if !reflect.DeepEqual(untracked, expectedUntracked) {
+		t.Errorf("untracked = %+v, want %+v", untracked, expectedUntracked)
+	}
+	if !modified[0].HasModeChange() || staged[0].HasModeChange() {
+		t.Error("HasModeChange() should only report script.sh")
+	}
+}
+
+func TestGetChangedFiles_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("original.txt", "line1\nline2\nline3\nline4\n")
+	runGit("add", ".")
+	runGit("commit", "-m", "initial")
+
+	runGit("mv", "original.txt", "a -> b.txt")
+	writeFile(" spaced.txt", "new\n")
+
+	staged, modified, untracked, err := GetChangedFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("GetChangedFiles failed: %v", err)
+	}
+
+	if len(staged) != 1 || staged[0].Path != "a -> b.txt" || staged[0].ChangeStatus != "renamed" || staged[0].OrigPath != "original.txt" {
+		t.Errorf("unexpected staged files: %+v", staged)
+	}
+	if len(modified) != 0 {
+		t.Errorf("unexpected modified files: %+v", modified)
+	}
+	if len(untracked) != 1 || untracked[0].Path != " spaced.txt" {
+		t.Errorf("unexpected untracked files: %+v", untracked)
+	}
+}
@@ -0,0 +1,39 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// CherryPick applies the changes of commit on top of HEAD. Merge commits are
+// picked relative to their first parent.
+func CherryPick(repoRoot, commit string) error {
+	args := []string{"cherry-pick"}
+	if isMerge(repoRoot, commit) {
+		args = append(args, "-m", "1")
+	}
+	return runGit(repoRoot, append(args, commit)...)
+}
+
+// Revert commits the inverse of commit on top of HEAD. Merge commits are
+// reverted relative to their first parent.
+func Revert(repoRoot, commit string) error {
+	args := []string{"revert", "--no-edit"}
+	if isMerge(repoRoot, commit) {
+		args = append(args, "-m", "1")
+	}
+	return runGit(repoRoot, append(args, commit)...)
+}
+
+// isMerge reports whether commit has more than one parent
+func isMerge(repoRoot, commit string) bool {
+	return refExists(repoRoot, commit+"^2")
+}
+
+// refExists reports whether rev resolves to an object
+func refExists(repoRoot, rev string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", rev)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(output)) != ""
+}
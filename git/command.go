@@ -0,0 +1,18 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runGit runs a git command that changes the repository and returns git's
+// own message as the error, so it can be shown to the user as is
+func runGit(repoRoot string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -2,27 +2,138 @@ package git
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 )
 
-func Commit(message string, repoRoot string) error {
-	// Execute git commit command
-	cmd := exec.Command("git", "commit", "-m", message)
+// CommitOptions are the flags of a commit made from the commit message area
+type CommitOptions struct {
+	Amend    bool // replace the HEAD commit
+	Signoff  bool // add a Signed-off-by trailer
+	NoVerify bool // skip the pre-commit and commit-msg hooks
+	GPGSign  bool // sign the commit (-S); --no-gpg-sign otherwise
+}
+
+// Commit records the staged changes with message. The message goes through
+// the prepare-commit-msg and commit-msg hooks like any git commit. When the
+// message still contains comment lines of the commit.template, they are
+// stripped unless commit.cleanup says otherwise.
+func Commit(message string, repoRoot string, options CommitOptions) error {
+	args := []string{"commit", "-m", message}
+	if configValue(repoRoot, "commit.cleanup") == "" {
+		if template, err := GetCommitTemplate(repoRoot); err == nil && hasTemplateComments(message, template) {
+			args = append(args, "--cleanup=strip")
+		}
+	}
+	if options.Amend {
+		args = append(args, "--amend")
+	}
+	if options.Signoff {
+		args = append(args, "--signoff")
+	}
+	if options.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	if options.GPGSign {
+		args = append(args, "-S")
+	} else {
+		args = append(args, "--no-gpg-sign")
+	}
+
+	cmd := exec.Command("git", args...)
 	cmd.Dir = repoRoot
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to commit: %s", string(output))
+		if options.Amend {
+			return fmt.Errorf("failed to amend commit: %s", strings.TrimSpace(string(output)))
+		}
+		return fmt.Errorf("failed to commit: %s", strings.TrimSpace(string(output)))
 	}
 	return nil
 }
 
-func CommitAmend(message string, repoRoot string) error {
-	// Execute git commit --amend command
-	cmd := exec.Command("git", "commit", "--amend", "-m", message)
-	cmd.Dir = repoRoot
-	output, err := cmd.CombinedOutput()
+// GetCommitTemplate returns the content of the configured commit.template,
+// or "" when none is set
+func GetCommitTemplate(repoRoot string) (string, error) {
+	path := configValue(repoRoot, "commit.template", "--path")
+	if path == "" {
+		return "", nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoRoot, path)
+	}
+	content, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("failed to amend commit: %s", string(output))
+		return "", fmt.Errorf("failed to read commit template: %w", err)
 	}
-	return nil
+	return string(content), nil
+}
+
+// hasTemplateComments reports whether message contains any of the comment
+// lines of template
+func hasTemplateComments(message, template string) bool {
+	messageLines := make(map[string]bool)
+	for _, line := range strings.Split(message, "\n") {
+		messageLines[strings.TrimRight(line, " \t")] = true
+	}
+	for _, line := range strings.Split(template, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if strings.HasPrefix(line, "#") && messageLines[line] {
+			return true
+		}
+	}
+	return false
+}
+
+// GPGSignByDefault reports whether commit.gpgSign is enabled
+func GPGSignByDefault(repoRoot string) bool {
+	return configValue(repoRoot, "commit.gpgSign", "--type=bool") == "true"
+}
+
+// SigningNeedsTerminal reports whether signing a commit may ask for the
+// passphrase on the terminal, i.e. gpg-agent uses a tty or curses pinentry.
+// Graphical pinentries and ssh or x509 signing prompt on their own.
+func SigningNeedsTerminal(repoRoot string) bool {
+	if format := configValue(repoRoot, "gpg.format"); format != "" && format != "openpgp" {
+		return false
+	}
+	program := pinentryProgram()
+	if program == "" {
+		return false
+	}
+	if resolved, err := filepath.EvalSymlinks(program); err == nil {
+		program = resolved
+	}
+	name := filepath.Base(program)
+	return strings.Contains(name, "tty") || strings.Contains(name, "curses")
+}
+
+// pinentryProgram returns the pinentry gpg-agent runs: pinentry-program from
+// gpg-agent.conf, or the pinentry on PATH
+func pinentryProgram() string {
+	if output, err := exec.Command("gpgconf", "--list-dirs", "homedir").Output(); err == nil {
+		conf := filepath.Join(strings.TrimSpace(string(output)), "gpg-agent.conf")
+		if content, err := os.ReadFile(conf); err == nil {
+			for _, line := range strings.Split(string(content), "\n") {
+				fields := strings.Fields(line)
+				if len(fields) >= 2 && fields[0] == "pinentry-program" {
+					return fields[1]
+				}
+			}
+		}
+	}
+	program, err := exec.LookPath("pinentry")
+	if err != nil {
+		return ""
+	}
+	return program
+}
+
+// HasStagedChanges reports whether the index differs from HEAD
+func HasStagedChanges(repoRoot string) bool {
+	cmd := exec.Command("git", "diff", "--cached", "--quiet")
+	cmd.Dir = repoRoot
+	return cmd.Run() != nil
 }
@@ -0,0 +1,78 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestCommit_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	writeFile := func(name, content string, perm os.FileMode) {
+		t.Helper()
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), perm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runGit("config", "commit.gpgSign", "false")
+
+	// Template
+	if template, err := GetCommitTemplate(tmpDir); err != nil || template != "" {
+		t.Fatalf("GetCommitTemplate() without template = %q, %v", template, err)
+	}
+	writeFile("template.txt", "Summary\n\n# Explain why\n", 0644)
+	runGit("config", "commit.template", "template.txt")
+	template, err := GetCommitTemplate(tmpDir)
+	if err != nil || template != "Summary\n\n# Explain why\n" {
+		t.Fatalf("GetCommitTemplate() = %q, %v", template, err)
+	}
+
+	writeFile("a.txt", "a\n", 0644)
+	runGit("add", "a.txt")
+	if err := Commit("first\n\n# Explain why\n", tmpDir, CommitOptions{Signoff: true}); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if got := runGit("log", "-1", "--format=%B"); got != "first\n\nSigned-off-by: Test User <test@example.com>" {
+		t.Errorf("message = %q, want the template comment stripped and a signoff", got)
+	}
+
+	// A commit-msg hook rejects the message and its output is returned
+	writeFile(".git/hooks/commit-msg", "#!/bin/sh\necho 'commit-msg: missing ticket number'\nexit 1\n", 0755)
+	writeFile("a.txt", "b\n", 0644)
+	runGit("add", "a.txt")
+	err = Commit("second", tmpDir, CommitOptions{})
+	if err == nil || !strings.Contains(err.Error(), "missing ticket number") {
+		t.Fatalf("Commit with a rejecting hook = %v, want the hook output", err)
+	}
+
+	// --no-verify skips it; amend replaces the commit
+	if err := Commit("second", tmpDir, CommitOptions{NoVerify: true}); err != nil {
+		t.Fatalf("Commit with NoVerify failed: %v", err)
+	}
+	if err := Commit("second, amended\n\n#42 stays in the message", tmpDir, CommitOptions{Amend: true, NoVerify: true}); err != nil {
+		t.Fatalf("amend failed: %v", err)
+	}
+	if got := runGit("log", "--format=%s"); got != "second, amended\nfirst" {
+		t.Errorf("history = %q", got)
+	}
+	// Without the template's comment lines, lines starting with # are kept
+	if got := runGit("log", "-1", "--format=%b"); got != "#42 stays in the message" {
+		t.Errorf("body = %q, want the # line kept", got)
+	}
+
+	if GPGSignByDefault(tmpDir) {
+		t.Error("expected commit.gpgSign=false to disable signing by default")
+	}
+	runGit("config", "commit.gpgSign", "yes")
+	if !GPGSignByDefault(tmpDir) {
+		t.Error("expected commit.gpgSign=yes to enable signing by default")
+	}
+}
@@ -0,0 +1,354 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictResolution selects which side of a conflict block to keep
+type ConflictResolution int
+
+const (
+	TakeOurs ConflictResolution = iota
+	TakeTheirs
+	TakeBoth
+)
+
+// ConflictBlock is a single <<<<<<< ... >>>>>>> region in a conflicted file
+type ConflictBlock struct {
+	Start       int // line index of the <<<<<<< marker (0-based)
+	End         int // line index of the >>>>>>> marker (0-based)
+	Ours        []string
+	Base        []string
+	Theirs      []string
+	HasBase     bool // true if the block has a ||||||| section (diff3 style)
+	OursLabel   string
+	TheirsLabel string
+}
+
+// ConflictStages holds the index stages of an unmerged path.
+// A nil field means the stage does not exist (e.g. the file was deleted on that side).
+type ConflictStages struct {
+	Base   *string // stage 1
+	Ours   *string // stage 2
+	Theirs *string // stage 3
+}
+
+// ParseConflictBlocks finds the conflict blocks in the given file content
+func ParseConflictBlocks(content string) []ConflictBlock {
+	var blocks []ConflictBlock
+	lines := strings.Split(content, "\n")
+
+	var current *ConflictBlock
+	section := 0 // 0: ours, 1: base, 2: theirs
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "<<<<<<<") && current == nil:
+			current = &ConflictBlock{Start: i, OursLabel: strings.TrimSpace(line[7:])}
+			section = 0
+		case current == nil:
+			continue
+		case strings.HasPrefix(line, "|||||||") && section == 0:
+			current.HasBase = true
+			section = 1
+		case strings.HasPrefix(line, "=======") && section < 2:
+			section = 2
+		case strings.HasPrefix(line, ">>>>>>>") && section == 2:
+			current.End = i
+			current.TheirsLabel = strings.TrimSpace(line[7:])
+			blocks = append(blocks, *current)
+			current = nil
+		default:
+			switch section {
+			case 0:
+				current.Ours = append(current.Ours, line)
+			case 1:
+				current.Base = append(current.Base, line)
+			default:
+				current.Theirs = append(current.Theirs, line)
+			}
+		}
+	}
+	return blocks
+}
+
+// ResolveConflictBlock replaces a conflict block with the chosen side and returns the new content
+func ResolveConflictBlock(content string, block ConflictBlock, resolution ConflictResolution) string {
+	lines := strings.Split(content, "\n")
+	if block.Start < 0 || block.End >= len(lines) || block.Start > block.End {
+		return content
+	}
+
+	var replacement []string
+	switch resolution {
+	case TakeOurs:
+		replacement = block.Ours
+	case TakeTheirs:
+		replacement = block.Theirs
+	case TakeBoth:
+		replacement = append(append([]string{}, block.Ours...), block.Theirs...)
+	}
+
+	result := make([]string, 0, len(lines))
+	result = append(result, lines[:block.Start]...)
+	result = append(result, replacement...)
+	result = append(result, lines[block.End+1:]...)
+	return strings.Join(result, "\n")
+}
+
+// ResolveConflict resolves the blockIndex-th conflict block of a file in the working tree
+func ResolveConflict(filePath, repoRoot string, blockIndex int, resolution ConflictResolution) error {
+	fullPath := filepath.Join(repoRoot, filePath)
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	blocks := ParseConflictBlocks(string(content))
+	if blockIndex < 0 || blockIndex >= len(blocks) {
+		return fmt.Errorf("no conflict block at index %d", blockIndex)
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	resolved := ResolveConflictBlock(string(content), blocks[blockIndex], resolution)
+	if err := os.WriteFile(fullPath, []byte(resolved), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// TakeConflictSide replaces the whole working tree file with one side of the conflict.
+// If that side deleted the file, the file is removed from the working tree.
+func TakeConflictSide(filePath, repoRoot string, resolution ConflictResolution) error {
+	stages, err := GetConflictStages(filePath, repoRoot)
+	if err != nil {
+		return err
+	}
+
+	side, flag := stages.Ours, "--ours"
+	if resolution == TakeTheirs {
+		side, flag = stages.Theirs, "--theirs"
+	} else if resolution != TakeOurs {
+		return fmt.Errorf("whole-file conflicts can only take ours or theirs")
+	}
+
+	if side == nil {
+		if err := os.Remove(filepath.Join(repoRoot, filePath)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove file: %w", err)
+		}
+		return nil
+	}
+
+	cmd := exec.Command("git", "checkout", flag, "--", filePath)
+	cmd.Dir = repoRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to checkout %s: %s", flag, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// MarkResolved stages the working tree version of a conflicted file.
+// It refuses while conflict markers remain in the file.
+func MarkResolved(filePath, repoRoot string) error {
+	content, err := os.ReadFile(filepath.Join(repoRoot, filePath))
+	if os.IsNotExist(err) {
+		// The file was resolved by deleting it
+		cmd := exec.Command("git", "rm", "--cached", "--quiet", "--", filePath)
+		cmd.Dir = repoRoot
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to mark resolved: %s", strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if blocks := ParseConflictBlocks(string(content)); len(blocks) > 0 {
+		return fmt.Errorf("%d conflict(s) remain in %s", len(blocks), filePath)
+	}
+
+	cmd := exec.Command("git", "add", "--", filePath)
+	cmd.Dir = repoRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to mark resolved: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// GetConflictStages reads the base, ours and theirs versions of an unmerged path from the index
+func GetConflictStages(filePath, repoRoot string) (ConflictStages, error) {
+	var stages ConflictStages
+
+	cmd := exec.Command("git", "ls-files", "--unmerged", "-z", "--", filePath)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return stages, fmt.Errorf("failed to execute git ls-files --unmerged: %w", err)
+	}
+
+	// Each entry is "<mode> <hash> <stage>\t<path>\0"
+	for _, entry := range strings.Split(string(output), "\x00") {
+		meta, _, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 3 {
+			continue
+		}
+
+		blob := exec.Command("git", "cat-file", "blob", fields[1])
+		blob.Dir = repoRoot
+		content, err := blob.Output()
+		if err != nil {
+			return stages, fmt.Errorf("failed to read stage %s: %w", fields[2], err)
+		}
+		text := string(content)
+
+		switch fields[2] {
+		case "1":
+			stages.Base = &text
+		case "2":
+			stages.Ours = &text
+		case "3":
+			stages.Theirs = &text
+		}
+	}
+	return stages, nil
+}
+
+// GetConflictDiff renders a conflicted file as a diff: ours lines are shown as
+// deletions, theirs lines as additions and base lines as context, one hunk per block
+func GetConflictDiff(filePath, repoRoot string) (string, error) {
+	stages, err := GetConflictStages(filePath, repoRoot)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(filepath.Join(repoRoot, filePath))
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	blocks := ParseConflictBlocks(string(content))
+	if len(blocks) == 0 {
+		return formatWholeFileConflict(stages), nil
+	}
+
+	// Fill in base sections from the index when the file uses the default "merge" style
+	if base := stageConflictBlocks(stages, repoRoot); len(base) == len(blocks) {
+		for i := range blocks {
+			if !blocks[i].HasBase && base[i].HasBase {
+				blocks[i].Base = base[i].Base
+				blocks[i].HasBase = true
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for _, block := range blocks {
+		// Markers and the base are context: ours is the old side, theirs the new
+		body := []string{" <<<<<<< " + labelOr(block.OursLabel, "ours")}
+		for _, line := range block.Ours {
+			body = append(body, "-"+line)
+		}
+		if block.HasBase {
+			body = append(body, " ||||||| base")
+			for _, line := range block.Base {
+				body = append(body, " "+line)
+			}
+		}
+		body = append(body, " =======")
+		for _, line := range block.Theirs {
+			body = append(body, "+"+line)
+		}
+		body = append(body, " >>>>>>> "+labelOr(block.TheirsLabel, "theirs"))
+
+		// Both sides start at the block in the working tree file
+		oldCount, newCount := 0, 0
+		for _, line := range body {
+			if line[0] != '+' {
+				oldCount++
+			}
+			if line[0] != '-' {
+				newCount++
+			}
+		}
+		sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", block.Start+1, oldCount, block.Start+1, newCount))
+		sb.WriteString(strings.Join(body, "\n") + "\n")
+	}
+	return sb.String(), nil
+}
+
+// formatWholeFileConflict renders conflicts without markers (e.g. modify/delete)
+// as a diff from ours to theirs
+func formatWholeFileConflict(stages ConflictStages) string {
+	var ours, theirs []string
+	if stages.Ours != nil {
+		ours = strings.Split(strings.TrimSuffix(*stages.Ours, "\n"), "\n")
+	}
+	if stages.Theirs != nil {
+		theirs = strings.Split(strings.TrimSuffix(*stages.Theirs, "\n"), "\n")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("@@ -1,%d +1,%d @@\n", len(ours), len(theirs)))
+	sb.WriteString("<<<<<<< ours" + deletedLabel(stages.Ours) + "\n")
+	for _, line := range ours {
+		sb.WriteString("-" + line + "\n")
+	}
+	sb.WriteString("=======\n")
+	for _, line := range theirs {
+		sb.WriteString("+" + line + "\n")
+	}
+	sb.WriteString(">>>>>>> theirs" + deletedLabel(stages.Theirs) + "\n")
+	return sb.String()
+}
+
+// stageConflictBlocks re-merges the index stages in diff3 style to recover base sections
+func stageConflictBlocks(stages ConflictStages, repoRoot string) []ConflictBlock {
+	if stages.Base == nil || stages.Ours == nil || stages.Theirs == nil {
+		return nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "giff-conflict-")
+	if err != nil {
+		return nil
+	}
+	defer os.RemoveAll(tmpDir)
+
+	paths := make([]string, 3)
+	for i, content := range []string{*stages.Ours, *stages.Base, *stages.Theirs} {
+		paths[i] = filepath.Join(tmpDir, fmt.Sprintf("stage%d", i))
+		if err := os.WriteFile(paths[i], []byte(content), 0644); err != nil {
+			return nil
+		}
+	}
+
+	// merge-file exits with the number of conflicts, so the error is expected
+	cmd := exec.Command("git", "merge-file", "-p", "--diff3", paths[0], paths[1], paths[2])
+	cmd.Dir = repoRoot
+	output, _ := cmd.Output()
+	return ParseConflictBlocks(string(output))
+}
+
+func labelOr(label, fallback string) string {
+	if label == "" {
+		return fallback
+	}
+	return label
+}
+
+func deletedLabel(stage *string) string {
+	if stage == nil {
+		return " (deleted)"
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+const conflictContent = `line1
+<<<<<<< HEAD
+ours
+||||||| base
+original
+=======
+theirs1
+theirs2
+>>>>>>> feature
+line2
+<<<<<<< HEAD
+second ours
+=======
+second theirs
+>>>>>>> feature
+line3`
+
+func TestParseConflictBlocks(t *testing.T) {
+	blocks := ParseConflictBlocks(conflictContent)
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(blocks))
+	}
+
+	expected := ConflictBlock{
+		Start:       1,
+		End:         8,
+		Ours:        []string{"ours"},
+		Base:        []string{"original"},
+		Theirs:      []string{"theirs1", "theirs2"},
+		HasBase:     true,
+		OursLabel:   "HEAD",
+		TheirsLabel: "feature",
+	}
+	if !reflect.DeepEqual(blocks[0], expected) {
+		t.Errorf("block 0 = %+v, want %+v", blocks[0], expected)
+	}
+	if blocks[1].Start != 10 || blocks[1].End != 14 || blocks[1].HasBase {
+		t.Errorf("unexpected block 1: %+v", blocks[1])
+	}
+}
+
+func TestResolveConflictBlock(t *testing.T) {
+	blocks := ParseConflictBlocks(conflictContent)
+
+	tests := []struct {
+		name       string
+		resolution ConflictResolution
+		expected   []string
+	}{
+		{"ours", TakeOurs, []string{"line1", "ours", "line2"}},
+		{"theirs", TakeTheirs, []string{"line1", "theirs1", "theirs2", "line2"}},
+		{"both", TakeBoth, []string{"line1", "ours", "theirs1", "theirs2", "line2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ResolveConflictBlock(conflictContent, blocks[0], tt.resolution)
+			lines := strings.Split(result, "\n")
+			if !reflect.DeepEqual(lines[:len(tt.expected)], tt.expected) {
+				t.Errorf("got %q, want prefix %q", lines, tt.expected)
+			}
+			// The second block is left untouched
+			if remaining := ParseConflictBlocks(result); len(remaining) != 1 {
+				t.Errorf("expected 1 remaining block, got %d", len(remaining))
+			}
+		})
+	}
+}
+
+func TestConflictResolution_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	writeFile := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, "file.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("a\nbase\nb\n")
+	runGit("add", ".")
+	runGit("commit", "-m", "initial")
+	runGit("checkout", "-b", "feature")
+	writeFile("a\ntheirs\nb\n")
+	runGit("commit", "-am", "theirs")
+	runGit("checkout", "main")
+	writeFile("a\nours\nb\n")
+	runGit("commit", "-am", "ours")
+
+	cmd := exec.Command("git", "merge", "feature")
+	cmd.Dir = tmpDir
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected merge conflict")
+	}
+
+	_, modified, _, err := GetChangedFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("GetChangedFiles failed: %v", err)
+	}
+	if len(modified) != 1 || modified[0].ChangeStatus != "conflicted" || modified[0].Conflict != "UU" {
+		t.Fatalf("unexpected modified files: %+v", modified)
+	}
+
+	stages, err := GetConflictStages("file.txt", tmpDir)
+	if err != nil {
+		t.Fatalf("GetConflictStages failed: %v", err)
+	}
+	if stages.Base == nil || *stages.Base != "a\nbase\nb\n" || stages.Ours == nil || stages.Theirs == nil {
+		t.Fatalf("unexpected stages: %+v", stages)
+	}
+
+	diff, err := GetConflictDiff("file.txt", tmpDir)
+	if err != nil {
+		t.Fatalf("GetConflictDiff failed: %v", err)
+	}
+	// Markers and the base are context lines counted on both sides
+	wantDiff := "@@ -2,6 +2,6 @@\n" +
+		" <<<<<<< HEAD\n" +
+		"-ours\n" +
+		" ||||||| base\n" +
+		" base\n" +
+		" =======\n" +
+		"+theirs\n" +
+		" >>>>>>> feature\n"
+	if diff != wantDiff {
+		t.Errorf("GetConflictDiff() =\n%s\nwant\n%s", diff, wantDiff)
+	}
+
+	if err := MarkResolved("file.txt", tmpDir); err == nil {
+		t.Error("MarkResolved should fail while markers remain")
+	}
+
+	if err := ResolveConflict("file.txt", tmpDir, 0, TakeTheirs); err != nil {
+		t.Fatalf("ResolveConflict failed: %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(tmpDir, "file.txt"))
+	if string(content) != "a\ntheirs\nb\n" {
+		t.Errorf("unexpected resolved content: %q", content)
+	}
+
+	if err := MarkResolved("file.txt", tmpDir); err != nil {
+		t.Fatalf("MarkResolved failed: %v", err)
+	}
+	staged, modified, _, _ := GetChangedFiles(tmpDir)
+	if len(modified) != 0 || len(staged) != 1 {
+		t.Errorf("expected file to be staged after resolving, got staged=%+v modified=%+v", staged, modified)
+	}
+}
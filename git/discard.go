@@ -0,0 +1,73 @@
+package git
+
+import (
+	"fmt"
+	"time"
+)
+
+// DiscardRecord is a discarded path whose content was saved before discarding
+type DiscardRecord struct {
+	Time  time.Time
+	Path  string               // file or directory that was discarded
+	Files map[string]FileState // saved content of every file below Path
+}
+
+// DiscardLog keeps the discards of the current session so they can be restored
+type DiscardLog struct {
+	records []DiscardRecord
+}
+
+// NewDiscardLog creates an empty discard log
+func NewDiscardLog() *DiscardLog {
+	return &DiscardLog{}
+}
+
+// Save stores the current content of path with hash-object -w and records it
+func (l *DiscardLog) Save(repoRoot, path string) error {
+	files, err := SaveFiles(repoRoot, path)
+	if err != nil {
+		return fmt.Errorf("failed to save %s before discarding: %w", path, err)
+	}
+	l.records = append(l.records, DiscardRecord{Time: time.Now(), Path: path, Files: files})
+	return nil
+}
+
+// Recent returns the records, newest first
+func (l *DiscardLog) Recent() []DiscardRecord {
+	recent := make([]DiscardRecord, len(l.records))
+	for i, record := range l.records {
+		recent[len(l.records)-1-i] = record
+	}
+	return recent
+}
+
+// Restore writes a record back into the working tree.
+// Files that changed since the discard are saved as a new record first,
+// so restoring never loses work either.
+func (l *DiscardLog) Restore(repoRoot string, record DiscardRecord) error {
+	current, err := SaveFiles(repoRoot, record.FilePaths()...)
+	if err != nil {
+		return err
+	}
+	replaced := map[string]FileState{}
+	for path, state := range current {
+		if state.Exists && state != record.Files[path] {
+			replaced[path] = state
+		}
+	}
+	if len(replaced) > 0 {
+		l.records = append(l.records, DiscardRecord{Time: time.Now(), Path: record.Path, Files: replaced})
+	}
+
+	for _, path := range record.FilePaths() {
+		if err := RestoreFileState(repoRoot, path, record.Files[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FilePaths returns the saved file paths in sorted order
+func (r DiscardRecord) FilePaths() []string {
+	return Snapshot{Files: r.Files}.FilePaths()
+}
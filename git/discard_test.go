@@ -0,0 +1,63 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestDiscardLog_RestoreUntrackedDirectory(t *testing.T) {
+	tmpDir, _ := gittest.NewTestRepo(t)
+
+	files := map[string]string{
+		"dir/a.txt":        "a\n",
+		"dir/nested/b.txt": "b\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(tmpDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	log := NewDiscardLog()
+	if err := log.Save(tmpDir, "dir"); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := os.RemoveAll(filepath.Join(tmpDir, "dir")); err != nil {
+		t.Fatal(err)
+	}
+
+	recent := log.Recent()
+	if len(recent) != 1 || recent[0].Path != "dir" || len(recent[0].Files) != 2 {
+		t.Fatalf("unexpected records: %+v", recent)
+	}
+
+	// A file recreated since the discard is kept as a new record when restoring
+	if err := os.MkdirAll(filepath.Join(tmpDir, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "dir/a.txt"), []byte("new work\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := log.Restore(tmpDir, recent[0]); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	for path, want := range files {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil || string(content) != want {
+			t.Errorf("%s = %q (err: %v), want %q", path, content, err, want)
+		}
+	}
+
+	recent = log.Recent()
+	if len(recent) != 2 || len(recent[0].Files) != 1 || !recent[0].Files["dir/a.txt"].Exists {
+		t.Fatalf("expected the replaced file to be saved, got %+v", recent)
+	}
+}
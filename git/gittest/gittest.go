@@ -0,0 +1,36 @@
+// Package gittest provides scratch git repositories for integration tests.
+package gittest
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// NewTestRepo creates an empty repository on branch main with a committer
+// configured, and returns its directory and a function running git in it.
+// run fails the test when git fails and returns the trimmed output. The test
+// is skipped when git is not installed.
+func NewTestRepo(t testing.TB) (dir string, run func(args ...string) string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	dir = t.TempDir()
+	run = func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	run("init", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+	return dir, run
+}
@@ -0,0 +1,93 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// FileRevision is a commit that touched a file, as listed by `git log --follow`
+type FileRevision struct {
+	Hash    string
+	Author  string
+	Date    string // relative date, e.g. "2 hours ago"
+	Subject string
+	Status  string // name-status of the file in this commit, e.g. "M" or "R100"
+	Path    string // path of the file in this commit
+	OldPath string // path before this commit, if it renamed the file
+}
+
+// GetFileHistory returns the commits that touched filePath, newest first,
+// following the file across renames
+func GetFileHistory(repoRoot, filePath string) ([]FileRevision, error) {
+	cmd := exec.Command("git", "-c", "core.quotepath=false", "log", "--follow", "--name-status",
+		"--format=%x1e%H%x1f%an%x1f%ar%x1f%s", "--", filePath)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute git log --follow: %w", err)
+	}
+	return parseFileHistory(string(output), filePath), nil
+}
+
+// parseFileHistory parses the output of GetFileHistory's git log.
+// Merge commits have no name-status, so they keep the path of the newer commit.
+func parseFileHistory(output, filePath string) []FileRevision {
+	var revisions []FileRevision
+	path := filePath
+	for _, record := range strings.Split(output, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		fields := strings.SplitN(lines[0], "\x1f", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		revision := FileRevision{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Date:    fields[2],
+			Subject: fields[3],
+			Path:    path,
+		}
+		for _, line := range lines[1:] {
+			// <status>\t<path> or <status>\t<old path>\t<new path>
+			parts := strings.Split(line, "\t")
+			if len(parts) < 2 {
+				continue
+			}
+			revision.Status = parts[0]
+			revision.Path = parts[len(parts)-1]
+			if len(parts) == 3 {
+				revision.OldPath = parts[1]
+			}
+		}
+		revisions = append(revisions, revision)
+
+		// Older commits know the file by its name before the rename
+		path = revision.Path
+		if revision.OldPath != "" {
+			path = revision.OldPath
+		}
+	}
+	return revisions
+}
+
+// GetFileRevisionDiff returns the diff of the file in a single commit of its history.
+// Merge commits are compared with their first parent.
+func GetFileRevisionDiff(repoRoot string, revision FileRevision, ignoreWhitespace bool) (string, error) {
+	args := []string{"-c", "core.quotepath=false", "show", "--format=", "-M", "--diff-merges=first-parent"}
+	if ignoreWhitespace {
+		args = append(args, "-w")
+	}
+	args = append(args, revision.Hash, "--", revision.Path)
+	if revision.OldPath != "" {
+		// Both paths are needed to show the change as a rename
+		args = append(args, revision.OldPath)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to execute git show %s: %w", revision.Hash, err)
+	}
+	return strings.TrimLeft(string(output), "\n"), nil
+}
@@ -0,0 +1,68 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestGetFileHistory_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content := "line1\nline2\nline3\nline4\nline5\n"
+	writeFile("old.txt", content)
+	runGit("add", "old.txt")
+	runGit("commit", "-m", "add old")
+
+	writeFile("other.txt", "other\n")
+	runGit("add", "other.txt")
+	runGit("commit", "-m", "unrelated")
+
+	runGit("mv", "old.txt", "new.txt")
+	runGit("commit", "-m", "rename")
+
+	writeFile("new.txt", content+"line6\n")
+	runGit("commit", "-am", "append")
+
+	revisions, err := GetFileHistory(tmpDir, "new.txt")
+	if err != nil {
+		t.Fatalf("GetFileHistory failed: %v", err)
+	}
+	if len(revisions) != 3 {
+		t.Fatalf("expected 3 revisions, got %+v", revisions)
+	}
+	if revisions[0].Subject != "append" || revisions[0].Path != "new.txt" || revisions[0].Status != "M" || revisions[0].Author != "Test User" {
+		t.Errorf("unexpected revision 0: %+v", revisions[0])
+	}
+	if revisions[1].Subject != "rename" || revisions[1].Path != "new.txt" || revisions[1].OldPath != "old.txt" || !strings.HasPrefix(revisions[1].Status, "R") {
+		t.Errorf("unexpected revision 1: %+v", revisions[1])
+	}
+	if revisions[2].Subject != "add old" || revisions[2].Path != "old.txt" || revisions[2].Status != "A" {
+		t.Errorf("unexpected revision 2: %+v", revisions[2])
+	}
+
+	diffText, err := GetFileRevisionDiff(tmpDir, revisions[1], false)
+	if err != nil {
+		t.Fatalf("GetFileRevisionDiff failed: %v", err)
+	}
+	if !strings.Contains(diffText, "rename from old.txt") || !strings.Contains(diffText, "rename to new.txt") {
+		t.Errorf("expected a rename diff, got:\n%s", diffText)
+	}
+
+	diffText, err = GetFileRevisionDiff(tmpDir, revisions[2], false)
+	if err != nil {
+		t.Fatalf("GetFileRevisionDiff failed: %v", err)
+	}
+	if !strings.Contains(diffText, "+++ b/old.txt") || !strings.Contains(diffText, "+line5") {
+		t.Errorf("expected the diff of old.txt, got:\n%s", diffText)
+	}
+}
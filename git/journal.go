@@ -0,0 +1,310 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxJournalEntries limits how many actions can be undone
+const maxJournalEntries = 100
+
+// FileState is the content of a working tree file stored as a git blob
+type FileState struct {
+	Exists bool
+	Hash   string // blob hash written with hash-object -w
+	Mode   os.FileMode
+}
+
+// Snapshot captures the parts of the repository an action can change.
+// Blobs referenced by Index and Files are written to the object database,
+// so a snapshot can be restored even after the working tree changed.
+type Snapshot struct {
+	Head  string               // HEAD commit ("" if the branch has no commits yet)
+	Index string               // tree written from the index with write-tree
+	Files map[string]FileState // working tree files touched by the action
+}
+
+// Equal reports whether two snapshots describe the same state
+func (s Snapshot) Equal(other Snapshot) bool {
+	if s.Head != other.Head || s.Index != other.Index || len(s.Files) != len(other.Files) {
+		return false
+	}
+	for path, state := range s.Files {
+		if other.Files[path] != state {
+			return false
+		}
+	}
+	return true
+}
+
+// FilePaths returns the working tree paths recorded in the snapshot
+func (s Snapshot) FilePaths() []string {
+	paths := make([]string, 0, len(s.Files))
+	for path := range s.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// JournalEntry is a reversible action
+type JournalEntry struct {
+	Description string
+	Before      Snapshot
+	After       Snapshot
+}
+
+// Journal records index-mutating actions so they can be undone and redone
+type Journal struct {
+	entries  []JournalEntry
+	position int // number of entries currently applied
+}
+
+// NewJournal creates an empty journal
+func NewJournal() *Journal {
+	return &Journal{}
+}
+
+// Record adds an entry, dropping any entries that were undone
+func (j *Journal) Record(description string, before, after Snapshot) {
+	if before.Equal(after) {
+		return
+	}
+	j.entries = append(j.entries[:j.position], JournalEntry{Description: description, Before: before, After: after})
+	if len(j.entries) > maxJournalEntries {
+		j.entries = j.entries[len(j.entries)-maxJournalEntries:]
+	}
+	j.position = len(j.entries)
+}
+
+// Undo restores the state before the last applied entry
+func (j *Journal) Undo(repoRoot string) (JournalEntry, error) {
+	if j.position == 0 {
+		return JournalEntry{}, fmt.Errorf("nothing to undo")
+	}
+	entry := j.entries[j.position-1]
+	if err := transition(entry.After, entry.Before, repoRoot); err != nil {
+		return entry, err
+	}
+	j.position--
+	return entry, nil
+}
+
+// Redo re-applies the next undone entry
+func (j *Journal) Redo(repoRoot string) (JournalEntry, error) {
+	if j.position >= len(j.entries) {
+		return JournalEntry{}, fmt.Errorf("nothing to redo")
+	}
+	entry := j.entries[j.position]
+	if err := transition(entry.Before, entry.After, repoRoot); err != nil {
+		return entry, err
+	}
+	j.position++
+	return entry, nil
+}
+
+// transition moves the repository from one snapshot to another,
+// refusing if the repository no longer matches the expected state
+func transition(from, to Snapshot, repoRoot string) error {
+	current, err := TakeSnapshot(repoRoot, from.FilePaths()...)
+	if err != nil {
+		return err
+	}
+	if !current.Equal(from) {
+		return fmt.Errorf("the repository has changed since this action")
+	}
+	return RestoreSnapshot(to, repoRoot)
+}
+
+// TakeSnapshot records HEAD, the index and the given working tree paths.
+// Directories are expanded to the files they contain.
+func TakeSnapshot(repoRoot string, paths ...string) (Snapshot, error) {
+	var snapshot Snapshot
+
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD")
+	cmd.Dir = repoRoot
+	if output, err := cmd.Output(); err == nil {
+		snapshot.Head = strings.TrimSpace(string(output))
+	}
+
+	cmd = exec.Command("git", "write-tree")
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return snapshot, fmt.Errorf("failed to write index tree: %w", err)
+	}
+	snapshot.Index = strings.TrimSpace(string(output))
+
+	snapshot.Files, err = SaveFiles(repoRoot, paths...)
+	if err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+// SaveFiles stores the given working tree paths as blobs.
+// Directories are expanded to the files they contain; missing paths are recorded as absent.
+func SaveFiles(repoRoot string, paths ...string) (map[string]FileState, error) {
+	files := map[string]FileState{}
+	for _, path := range paths {
+		if err := snapshotPath(repoRoot, path, files); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// snapshotPath stores a file, or every file below a directory, as blobs
+func snapshotPath(repoRoot, path string, files map[string]FileState) error {
+	fullPath := filepath.Join(repoRoot, path)
+	info, err := os.Lstat(fullPath)
+	if os.IsNotExist(err) {
+		files[path] = FileState{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		hash, err := writeWorkingTreeBlob(fullPath, info)
+		if err != nil {
+			return err
+		}
+		files[path] = FileState{Exists: true, Hash: hash, Mode: info.Mode()}
+		return nil
+	}
+
+	return filepath.WalkDir(fullPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(repoRoot, p)
+		if err != nil {
+			return err
+		}
+		return snapshotPath(repoRoot, filepath.ToSlash(rel), files)
+	})
+}
+
+// HasNestedRepository reports whether path is a directory holding a nested
+// repository or worktree. Snapshots leave its .git out, so deleting such a
+// directory could not be undone.
+func HasNestedRepository(repoRoot, path string) bool {
+	found := false
+	filepath.WalkDir(filepath.Join(repoRoot, path), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Name() == ".git" {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// writeWorkingTreeBlob stores a file (or symlink target) in the object database
+func writeWorkingTreeBlob(fullPath string, info os.FileInfo) (string, error) {
+	var content []byte
+	var err error
+	if info.Mode()&os.ModeSymlink != 0 {
+		var target string
+		target, err = os.Readlink(fullPath)
+		content = []byte(target)
+	} else {
+		content, err = os.ReadFile(fullPath)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", fullPath, err)
+	}
+
+	cmd := exec.Command("git", "hash-object", "-w", "--stdin")
+	cmd.Dir = filepath.Dir(fullPath)
+	cmd.Stdin = bytes.NewReader(content)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to store %s: %w", fullPath, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RestoreSnapshot moves HEAD, the index and the recorded files back to the snapshot
+func RestoreSnapshot(s Snapshot, repoRoot string) error {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD")
+	cmd.Dir = repoRoot
+	output, _ := cmd.Output()
+	if strings.TrimSpace(string(output)) != s.Head {
+		if s.Head == "" {
+			cmd = exec.Command("git", "update-ref", "-d", "HEAD")
+		} else {
+			cmd = exec.Command("git", "update-ref", "-m", "giff: undo/redo", "HEAD", s.Head)
+		}
+		cmd.Dir = repoRoot
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to update HEAD: %s", strings.TrimSpace(string(output)))
+		}
+	}
+
+	cmd = exec.Command("git", "read-tree", s.Index)
+	cmd.Dir = repoRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to restore index: %s", strings.TrimSpace(string(output)))
+	}
+
+	for _, path := range s.FilePaths() {
+		if err := RestoreFileState(repoRoot, path, s.Files[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreFileState writes a stored file back into the working tree (or removes it)
+func RestoreFileState(repoRoot, path string, state FileState) error {
+	fullPath := filepath.Join(repoRoot, path)
+	if !state.Exists {
+		if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		return nil
+	}
+
+	cmd := exec.Command("git", "cat-file", "blob", state.Hash)
+	cmd.Dir = repoRoot
+	content, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to read blob for %s: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	// Replace the file so a symlink is never written through
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	if state.Mode&os.ModeSymlink != 0 {
+		if err := os.Symlink(string(content), fullPath); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(fullPath, content, state.Mode.Perm()); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestJournal_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	filePath := filepath.Join(tmpDir, "file.txt")
+
+	if err := os.WriteFile(filePath, []byte("original\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit("add", ".")
+	runGit("commit", "-m", "initial")
+	initialHead := runGit("rev-parse", "HEAD")
+
+	journal := NewJournal()
+	record := func(description string, paths []string, action func()) {
+		t.Helper()
+		before, err := TakeSnapshot(tmpDir, paths...)
+		if err != nil {
+			t.Fatalf("TakeSnapshot failed: %v", err)
+		}
+		action()
+		after, err := TakeSnapshot(tmpDir, before.FilePaths()...)
+		if err != nil {
+			t.Fatalf("TakeSnapshot failed: %v", err)
+		}
+		journal.Record(description, before, after)
+	}
+
+	// Stage and commit a change
+	if err := os.WriteFile(filePath, []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	record("stage", nil, func() { runGit("add", "file.txt") })
+	record("commit", nil, func() { runGit("commit", "-m", "change") })
+
+	// Undo the commit: HEAD moves back, the change stays staged
+	if _, err := journal.Undo(tmpDir); err != nil {
+		t.Fatalf("Undo commit failed: %v", err)
+	}
+	if head := runGit("rev-parse", "HEAD"); head != initialHead {
+		t.Errorf("HEAD = %s, want %s", head, initialHead)
+	}
+	if staged := runGit("diff", "--cached", "--name-only"); staged != "file.txt" {
+		t.Errorf("expected file.txt to stay staged, got %q", staged)
+	}
+
+	// Undo the stage
+	if _, err := journal.Undo(tmpDir); err != nil {
+		t.Fatalf("Undo stage failed: %v", err)
+	}
+	if staged := runGit("diff", "--cached", "--name-only"); staged != "" {
+		t.Errorf("expected nothing staged, got %q", staged)
+	}
+	if _, err := journal.Undo(tmpDir); err == nil {
+		t.Error("expected nothing to undo")
+	}
+
+	// Redo both
+	for i := 0; i < 2; i++ {
+		if _, err := journal.Redo(tmpDir); err != nil {
+			t.Fatalf("Redo failed: %v", err)
+		}
+	}
+	if subject := runGit("log", "-1", "--format=%s"); subject != "change" {
+		t.Errorf("expected redone commit, got %q", subject)
+	}
+
+	// Discard a working tree change and bring it back
+	if err := os.WriteFile(filePath, []byte("uncommitted\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	record("discard", []string{"file.txt"}, func() { runGit("checkout", "--", "file.txt") })
+	if content, _ := os.ReadFile(filePath); string(content) != "changed\n" {
+		t.Fatalf("discard did not happen: %q", content)
+	}
+	if _, err := journal.Undo(tmpDir); err != nil {
+		t.Fatalf("Undo discard failed: %v", err)
+	}
+	if content, _ := os.ReadFile(filePath); string(content) != "uncommitted\n" {
+		t.Errorf("expected discarded content to be restored, got %q", content)
+	}
+
+	// Undo refuses once the repository diverged from the recorded state
+	if err := os.WriteFile(filePath, []byte("edited again\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := journal.Redo(tmpDir); err == nil {
+		t.Error("expected redo to fail after the file changed")
+	}
+}
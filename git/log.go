@@ -0,0 +1,136 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// LogEntry is a commit listed by GetLog
+type LogEntry struct {
+	Hash    string
+	Parents []string
+	Author  string
+	Date    string   // relative date, e.g. "2 days ago"
+	Refs    []string // decorations, e.g. "HEAD -> main", "tag: v1.0"
+	Subject string
+}
+
+// LogFilter narrows down the commits listed by GetLog
+type LogFilter struct {
+	Author  string // --author pattern
+	Message string // --grep pattern
+	Path    string // only commits touching this path
+	Since   string // --since date, e.g. "2024-01-01" or "2 weeks ago"
+	Until   string // --until date
+	Branch  string // list from this revision instead of HEAD
+	All     bool   // list from all refs
+}
+
+// IsEmpty reports whether the filter lists every commit reachable from HEAD
+func (f LogFilter) IsEmpty() bool {
+	return f == LogFilter{}
+}
+
+// LimitsCommits reports whether the filter drops commits from the middle of
+// the history, which leaves their children without parents to draw a graph to
+func (f LogFilter) LimitsCommits() bool {
+	return f.Author != "" || f.Message != ""
+}
+
+// String summarizes the active filters
+func (f LogFilter) String() string {
+	var parts []string
+	for _, item := range []struct{ name, value string }{
+		{"author", f.Author}, {"message", f.Message}, {"path", f.Path},
+		{"since", f.Since}, {"until", f.Until}, {"branch", f.Branch},
+	} {
+		if item.value != "" {
+			parts = append(parts, item.name+":"+item.value)
+		}
+	}
+	if f.All {
+		parts = append(parts, "all refs")
+	}
+	return strings.Join(parts, " ")
+}
+
+// GetLog returns up to count commits of the filtered log in date order,
+// skipping the first skip, so the log can be loaded a page at a time
+func GetLog(repoRoot string, filter LogFilter, skip, count int) ([]LogEntry, error) {
+	args, err := logArgs(filter, skip, count)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+	return parseLog(string(output)), nil
+}
+
+// logFieldCount is the number of NUL-separated fields per commit in logArgs
+const logFieldCount = 6
+
+// logArgs returns the git log arguments for a page of the filtered log.
+// The branch comes from user input, so one that git would read as an
+// option is rejected.
+func logArgs(filter LogFilter, skip, count int) ([]string, error) {
+	if strings.HasPrefix(filter.Branch, "-") {
+		return nil, fmt.Errorf("invalid branch: %s", filter.Branch)
+	}
+	args := []string{"-c", "core.quotepath=false", "log", "-z",
+		"--format=%H%x00%P%x00%an%x00%cr%x00%D%x00%s", "--date-order",
+		"--skip=" + strconv.Itoa(skip), "-n", strconv.Itoa(count)}
+	if filter.Author != "" || filter.Message != "" {
+		args = append(args, "--regexp-ignore-case")
+	}
+	if filter.Author != "" {
+		args = append(args, "--author="+filter.Author)
+	}
+	if filter.Message != "" {
+		args = append(args, "--grep="+filter.Message)
+	}
+	if filter.Since != "" {
+		args = append(args, "--since="+filter.Since)
+	}
+	if filter.Until != "" {
+		args = append(args, "--until="+filter.Until)
+	}
+	if filter.All {
+		args = append(args, "--all")
+	} else if filter.Branch != "" {
+		args = append(args, filter.Branch)
+	}
+	if filter.Path != "" {
+		// Rewrite parents to the previous commit touching the path, so the graph stays connected
+		args = append(args, "--parents", "--", filter.Path)
+	} else {
+		args = append(args, "--")
+	}
+	return args, nil
+}
+
+// parseLog parses the output of logArgs: with -z every field and every
+// commit is separated by a NUL
+func parseLog(output string) []LogEntry {
+	fields := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+	entries := []LogEntry{}
+	for i := 0; i+logFieldCount <= len(fields); i += logFieldCount {
+		entry := LogEntry{
+			Hash:    strings.TrimSpace(fields[i]),
+			Parents: strings.Fields(fields[i+1]),
+			Author:  fields[i+2],
+			Date:    fields[i+3],
+			Subject: fields[i+5],
+		}
+		if fields[i+4] != "" {
+			entry.Refs = strings.Split(fields[i+4], ", ")
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
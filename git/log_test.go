@@ -0,0 +1,54 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLog(t *testing.T) {
+	output := "aaa111\x00bbb222\x00Alice\x002 days ago\x00HEAD -> main, tag: v1.0\x00Fix deadbeef parsing\x00" +
+		"bbb222\x00ccc333 ddd444\x00Bob\x003 days ago\x00\x00Merge branch 'topic'\x00" +
+		"ccc333\x00\x00Carol\x001 year ago\x00\x00Initial commit\x00"
+
+	want := []LogEntry{
+		{Hash: "aaa111", Parents: []string{"bbb222"}, Author: "Alice", Date: "2 days ago", Refs: []string{"HEAD -> main", "tag: v1.0"}, Subject: "Fix deadbeef parsing"},
+		{Hash: "bbb222", Parents: []string{"ccc333", "ddd444"}, Author: "Bob", Date: "3 days ago", Subject: "Merge branch 'topic'"},
+		{Hash: "ccc333", Parents: []string{}, Author: "Carol", Date: "1 year ago", Subject: "Initial commit"},
+	}
+	if got := parseLog(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseLog() = %+v, want %+v", got, want)
+	}
+
+	if got := parseLog(""); len(got) != 0 {
+		t.Errorf("parseLog(\"\") = %+v, want no entries", got)
+	}
+}
+
+func TestLogArgs(t *testing.T) {
+	args, err := logArgs(LogFilter{Author: "alice", Message: "fix", Path: "ui", Since: "2 weeks ago", Branch: "topic"}, 200, 100)
+	if err != nil {
+		t.Fatalf("logArgs() error = %v", err)
+	}
+	want := []string{"-c", "core.quotepath=false", "log", "-z",
+		"--format=%H%x00%P%x00%an%x00%cr%x00%D%x00%s", "--date-order", "--skip=200", "-n", "100",
+		"--regexp-ignore-case", "--author=alice", "--grep=fix", "--since=2 weeks ago", "topic", "--parents", "--", "ui"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("logArgs() = %q, want %q", args, want)
+	}
+
+	// --all wins over a branch
+	args, err = logArgs(LogFilter{Branch: "topic", All: true}, 0, 100)
+	if err != nil {
+		t.Fatalf("logArgs() error = %v", err)
+	}
+	want = []string{"-c", "core.quotepath=false", "log", "-z",
+		"--format=%H%x00%P%x00%an%x00%cr%x00%D%x00%s", "--date-order", "--skip=0", "-n", "100", "--all", "--"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("logArgs() = %q, want %q", args, want)
+	}
+
+	// A branch that git would read as an option is rejected
+	if args, err := logArgs(LogFilter{Branch: "--output=/tmp/log"}, 0, 100); err == nil {
+		t.Errorf("logArgs() with an option as branch = %q, want an error", args)
+	}
+}
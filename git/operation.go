@@ -0,0 +1,165 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Operation is a multi-step git command that stopped and waits to be
+// continued, skipped or aborted
+type Operation struct {
+	Name  string // "rebase", "am", "merge", "cherry-pick", "revert" or "bisect"
+	Step  int    // current step of a rebase or am, 0 when unknown
+	Total int    // number of steps of a rebase or am, 0 when unknown
+}
+
+// String returns the operation with its progress, e.g. "rebase 3/7"
+func (op *Operation) String() string {
+	if op.Total > 0 {
+		return fmt.Sprintf("%s %d/%d", op.Name, op.Step, op.Total)
+	}
+	return op.Name
+}
+
+// CanContinue reports whether the operation has a --continue step. A bisect
+// only moves on when a commit is marked good or bad.
+func (op *Operation) CanContinue() bool {
+	return op.Name != "bisect"
+}
+
+// CanSkip reports whether the current step of the operation can be skipped
+func (op *Operation) CanSkip() bool {
+	return op.Name != "merge"
+}
+
+// CurrentOperation detects the operation in progress from the state files in
+// the git directory, or returns nil when there is none
+func CurrentOperation(repoRoot string) *Operation {
+	gitDir, err := absoluteGitDir(repoRoot)
+	if err != nil {
+		return nil
+	}
+	if dir := filepath.Join(gitDir, "rebase-merge"); pathExists(dir) {
+		return &Operation{
+			Name:  "rebase",
+			Step:  readNumber(filepath.Join(dir, "msgnum")),
+			Total: readNumber(filepath.Join(dir, "end")),
+		}
+	}
+	if dir := filepath.Join(gitDir, "rebase-apply"); pathExists(dir) {
+		name := "rebase"
+		if pathExists(filepath.Join(dir, "applying")) {
+			name = "am"
+		}
+		return &Operation{
+			Name:  name,
+			Step:  readNumber(filepath.Join(dir, "next")),
+			Total: readNumber(filepath.Join(dir, "last")),
+		}
+	}
+	for _, state := range []struct{ file, name string }{
+		{"MERGE_HEAD", "merge"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+		{"BISECT_LOG", "bisect"},
+	} {
+		if pathExists(filepath.Join(gitDir, state.file)) {
+			return &Operation{Name: state.name}
+		}
+	}
+	return nil
+}
+
+// RebaseInProgress reports whether a rebase stopped and waits to be
+// continued or aborted
+func RebaseInProgress(repoRoot string) bool {
+	return operationIs(repoRoot, "rebase")
+}
+
+// CherryPickInProgress reports whether a cherry-pick stopped on conflicts
+func CherryPickInProgress(repoRoot string) bool {
+	return operationIs(repoRoot, "cherry-pick")
+}
+
+// RevertInProgress reports whether a revert stopped on conflicts
+func RevertInProgress(repoRoot string) bool {
+	return operationIs(repoRoot, "revert")
+}
+
+// operationIs reports whether the operation in progress is name
+func operationIs(repoRoot, name string) bool {
+	op := CurrentOperation(repoRoot)
+	return op != nil && op.Name == name
+}
+
+// ContinueOperation continues op after its conflicts were resolved. Commit
+// messages are kept as git prepared them.
+func ContinueOperation(repoRoot string, op *Operation) error {
+	if !op.CanContinue() {
+		return fmt.Errorf("a %s cannot be continued", op.Name)
+	}
+	return runOperation(repoRoot, op.Name, "--continue")
+}
+
+// SkipOperation skips the current step of op: the commit being applied, or
+// the commit being tested by a bisect
+func SkipOperation(repoRoot string, op *Operation) error {
+	if !op.CanSkip() {
+		return fmt.Errorf("a %s cannot be skipped", op.Name)
+	}
+	if op.Name == "bisect" {
+		return runGit(repoRoot, "bisect", "skip")
+	}
+	return runOperation(repoRoot, op.Name, "--skip")
+}
+
+// AbortOperation abandons op and goes back to where it started
+func AbortOperation(repoRoot string, op *Operation) error {
+	if op.Name == "bisect" {
+		return runGit(repoRoot, "bisect", "reset")
+	}
+	return runOperation(repoRoot, op.Name, "--abort")
+}
+
+// runOperation runs "git <name> <flag>" without opening an editor
+func runOperation(repoRoot, name, flag string) error {
+	cmd := exec.Command("git", name, flag)
+	cmd.Dir = repoRoot
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// absoluteGitDir returns the git directory of the worktree at repoRoot, which
+// holds the state files of operations in progress
+func absoluteGitDir(repoRoot string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--absolute-git-dir")
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the git directory: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// pathExists reports whether path exists
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// readNumber reads a file holding a single number, or returns 0
+func readNumber(path string) int {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(string(content)))
+	return n
+}
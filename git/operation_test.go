@@ -0,0 +1,121 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestOperation_String(t *testing.T) {
+	tests := []struct {
+		op   Operation
+		want string
+	}{
+		{Operation{Name: "rebase", Step: 3, Total: 7}, "rebase 3/7"},
+		{Operation{Name: "merge"}, "merge"},
+	}
+	for _, tt := range tests {
+		if got := tt.op.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCurrentOperation_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	commitFile := func(name, content, message string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit("add", name)
+		runGit("commit", "-m", message)
+	}
+	// tryGit runs a command that is expected to stop on conflicts
+	tryGit := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+		cmd.Run()
+	}
+
+	commitFile("a.txt", "base\n", "base")
+	runGit("checkout", "-b", "topic")
+	commitFile("a.txt", "topic\n", "topic a")
+	commitFile("b.txt", "b\n", "add b")
+	runGit("checkout", "main")
+	commitFile("a.txt", "main\n", "main a")
+
+	if op := CurrentOperation(tmpDir); op != nil {
+		t.Fatalf("CurrentOperation() = %v, want none", op)
+	}
+
+	// Rebase: stops on the first of two commits, skip it and continue with the rest
+	runGit("checkout", "topic")
+	tryGit("rebase", "main")
+	op := CurrentOperation(tmpDir)
+	if op == nil || op.String() != "rebase 1/2" {
+		t.Fatalf("CurrentOperation() = %v, want rebase 1/2", op)
+	}
+	if err := ContinueOperation(tmpDir, op); err == nil {
+		t.Error("expected continuing with unresolved conflicts to fail")
+	}
+	if err := SkipOperation(tmpDir, op); err != nil {
+		t.Fatalf("SkipOperation failed: %v", err)
+	}
+	if op := CurrentOperation(tmpDir); op != nil {
+		t.Fatalf("CurrentOperation() after skip = %v, want none", op)
+	}
+	if got := runGit("log", "-1", "--format=%s"); got != "add b" {
+		t.Errorf("HEAD after rebase = %q, want %q", got, "add b")
+	}
+
+	// Merge: cannot be skipped, abort restores HEAD
+	runGit("reset", "--hard", "main~1")
+	commitFile("a.txt", "other\n", "other a")
+	head := runGit("rev-parse", "HEAD")
+	tryGit("merge", "main")
+	op = CurrentOperation(tmpDir)
+	if op == nil || op.Name != "merge" || op.CanSkip() {
+		t.Fatalf("CurrentOperation() = %+v, want a merge that cannot be skipped", op)
+	}
+	if err := AbortOperation(tmpDir, op); err != nil {
+		t.Fatalf("AbortOperation failed: %v", err)
+	}
+	if got := runGit("rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD after abort = %s, want %s", got, head)
+	}
+
+	// Resolved cherry-pick continues without an editor
+	tryGit("cherry-pick", "main")
+	op = CurrentOperation(tmpDir)
+	if op == nil || op.Name != "cherry-pick" {
+		t.Fatalf("CurrentOperation() = %+v, want cherry-pick", op)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.txt"), []byte("resolved\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit("add", "a.txt")
+	if err := ContinueOperation(tmpDir, op); err != nil {
+		t.Fatalf("ContinueOperation failed: %v", err)
+	}
+	if got := runGit("log", "-1", "--format=%s"); got != "main a" {
+		t.Errorf("HEAD after cherry-pick = %q, want %q", got, "main a")
+	}
+
+	// Bisect: no continue, abort resets it
+	runGit("bisect", "start", "HEAD", "main~1")
+	op = CurrentOperation(tmpDir)
+	if op == nil || op.Name != "bisect" || op.CanContinue() {
+		t.Fatalf("CurrentOperation() = %+v, want a bisect that cannot be continued", op)
+	}
+	if err := AbortOperation(tmpDir, op); err != nil {
+		t.Fatalf("AbortOperation failed: %v", err)
+	}
+	if op := CurrentOperation(tmpDir); op != nil {
+		t.Errorf("CurrentOperation() after bisect reset = %v, want none", op)
+	}
+}
@@ -0,0 +1,172 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// GetCommitMessage returns the full message of a commit
+func GetCommitMessage(repoRoot, commit string) (string, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%B", commit)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get message of %s: %w", commit, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// FixupCommit commits the staged changes as a fixup! of commit and folds
+// them into it with an autosquash rebase
+func FixupCommit(repoRoot, commit string) error {
+	if err := CheckRewritable(repoRoot, commit); err != nil {
+		return err
+	}
+	hash, err := resolveCommit(repoRoot, commit)
+	if err != nil {
+		return err
+	}
+	// Name the target by hash rather than subject (what --fixup writes), so
+	// autosquash picks the right commit even when subjects repeat
+	if err := runGit(repoRoot, "commit", "-m", "fixup! "+hash); err != nil {
+		return err
+	}
+	return autosquashRebase(repoRoot, hash)
+}
+
+// RewordCommit replaces the message of commit with an amend! commit and an
+// autosquash rebase; staged changes are not committed
+func RewordCommit(repoRoot, commit, message string) error {
+	if err := CheckRewritable(repoRoot, commit); err != nil {
+		return err
+	}
+	hash, err := resolveCommit(repoRoot, commit)
+	if err != nil {
+		return err
+	}
+
+	// git commit --fixup=reword: cannot take -m, so write the amend! message
+	// ourselves; --only with no paths leaves the staged changes out
+	amend := "amend! " + hash + "\n\n" + message
+	if err := runGit(repoRoot, "commit", "--allow-empty", "--only", "-m", amend); err != nil {
+		return err
+	}
+	return autosquashRebase(repoRoot, hash)
+}
+
+// SquashIntoParent melds commit into its parent, keeping both messages
+func SquashIntoParent(repoRoot, commit string) error {
+	if err := CheckRewritable(repoRoot, commit); err != nil {
+		return err
+	}
+	hash, err := resolveCommit(repoRoot, commit)
+	if err != nil {
+		return err
+	}
+	parent, err := resolveCommit(repoRoot, hash+"^")
+	if err != nil {
+		return fmt.Errorf("%s has no parent to squash into", shortCommit(hash))
+	}
+	return rebaseWithAction(repoRoot, parent, hash, "squash")
+}
+
+// DropCommit removes commit from the current branch
+func DropCommit(repoRoot, commit string) error {
+	if err := CheckRewritable(repoRoot, commit); err != nil {
+		return err
+	}
+	hash, err := resolveCommit(repoRoot, commit)
+	if err != nil {
+		return err
+	}
+	return rebaseWithAction(repoRoot, hash, hash, "drop")
+}
+
+// autosquashRebase rebases the commits from commit to HEAD onto the parent of
+// commit without opening an editor, folding the fixup! and amend! commits
+// into their targets. Local changes are autostashed and come back unstaged.
+func autosquashRebase(repoRoot, commit string) error {
+	cmd := rebaseCommand(repoRoot, commit, "true", "--autosquash")
+	cmd.Env = append(cmd.Env, "GIT_EDITOR=true")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// rebaseCommand prepares an interactive rebase of the commits from commit to
+// HEAD onto the parent of commit, with sequenceEditor editing the todo list
+func rebaseCommand(repoRoot, commit, sequenceEditor string, extraArgs ...string) *exec.Cmd {
+	args := []string{"-c", "core.abbrev=40", "-c", "rebase.abbreviateCommands=false",
+		"rebase", "--interactive", "--autostash"}
+	args = append(args, extraArgs...)
+	if parent, err := resolveCommit(repoRoot, commit+"^"); err == nil {
+		args = append(args, parent)
+	} else {
+		args = append(args, "--root")
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	cmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR="+sequenceEditor)
+	return cmd
+}
+
+// rebaseWithAction rebases the commits from start to HEAD with every commit
+// picked except hash, which gets action; squash messages are combined as git
+// proposes them
+func rebaseWithAction(repoRoot, start, hash, action string) error {
+	items, err := GetRebaseTodo(repoRoot, start)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		if items[i].Hash == hash {
+			items[i].Action = action
+		}
+	}
+	return RunRebaseTodo(repoRoot, start, items, false)
+}
+
+// CheckRewritable refuses commits that a linear rebase of the current branch
+// cannot rewrite
+func CheckRewritable(repoRoot, commit string) error {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", commit, "HEAD")
+	cmd.Dir = repoRoot
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s is not on the current branch", shortCommit(commit))
+	}
+
+	// The rebase would flatten merges between commit and HEAD
+	cmd = exec.Command("git", "rev-list", "--merges", "-n", "1", "HEAD", "--not", commit+"^@")
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to list merges after %s: %w", shortCommit(commit), err)
+	}
+	if merge := strings.TrimSpace(string(output)); merge != "" {
+		return fmt.Errorf("cannot rewrite history through merge commit %s", shortCommit(merge))
+	}
+	return nil
+}
+
+// resolveCommit returns the full hash of a commit
+func resolveCommit(repoRoot, rev string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("unknown commit %s", rev)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// shortCommit abbreviates a full hash for messages
+func shortCommit(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,112 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestRewriteCommits_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commitFile := func(name, content, message string) string {
+		t.Helper()
+		writeFile(name, content)
+		runGit("add", name)
+		runGit("commit", "-m", message)
+		return runGit("rev-parse", "HEAD")
+	}
+	subjects := func() string {
+		t.Helper()
+		return strings.ReplaceAll(runGit("log", "--format=%s"), "\n", ",")
+	}
+
+	first := commitFile("a.txt", "a\n", "first")
+	second := commitFile("b.txt", "b\n", "second")
+	commitFile("c.txt", "c\n", "third")
+
+	// Fixup: the staged change ends up in the second commit
+	writeFile("b.txt", "b fixed\n")
+	runGit("add", "b.txt")
+	if err := FixupCommit(tmpDir, second); err != nil {
+		t.Fatalf("FixupCommit failed: %v", err)
+	}
+	if got := subjects(); got != "third,second,first" {
+		t.Errorf("subjects after fixup = %q", got)
+	}
+	if got := runGit("show", "HEAD~1:b.txt"); got != "b fixed" {
+		t.Errorf("b.txt in second commit = %q, want %q", got, "b fixed")
+	}
+
+	// Reword: staged changes stay out of the rewritten commit and come back
+	// from the autostash
+	writeFile("a.txt", "a staged\n")
+	runGit("add", "a.txt")
+	if err := RewordCommit(tmpDir, runGit("rev-parse", "HEAD~2"), "first, reworded\n\nwith a body"); err != nil {
+		t.Fatalf("RewordCommit failed: %v", err)
+	}
+	message, err := GetCommitMessage(tmpDir, "HEAD~2")
+	if err != nil || message != "first, reworded\n\nwith a body" {
+		t.Errorf("GetCommitMessage = %q (err: %v)", message, err)
+	}
+	if got := subjects(); got != "third,second,first, reworded" {
+		t.Errorf("subjects after reword = %q", got)
+	}
+	if got := runGit("diff", "HEAD", "--name-only"); got != "a.txt" {
+		t.Errorf("changed files after reword = %q, want a.txt", got)
+	}
+	runGit("reset", "--hard")
+
+	// Squash the third commit into the second: both messages are kept
+	if err := SquashIntoParent(tmpDir, "HEAD"); err != nil {
+		t.Fatalf("SquashIntoParent failed: %v", err)
+	}
+	if got := subjects(); got != "second,first, reworded" {
+		t.Errorf("subjects after squash = %q", got)
+	}
+	if message, _ := GetCommitMessage(tmpDir, "HEAD"); !strings.Contains(message, "third") {
+		t.Errorf("squashed message %q lost the third commit", message)
+	}
+	if err := SquashIntoParent(tmpDir, "HEAD~1"); err == nil {
+		t.Error("expected squashing the root commit to fail")
+	}
+
+	// Drop
+	commitFile("d.txt", "d\n", "fourth")
+	if err := DropCommit(tmpDir, "HEAD~1"); err != nil {
+		t.Fatalf("DropCommit failed: %v", err)
+	}
+	if got := subjects(); got != "fourth,first, reworded" {
+		t.Errorf("subjects after drop = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "b.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected b.txt to be gone with the dropped commit, got %v", err)
+	}
+
+	// Commits outside the current branch are refused
+	if err := DropCommit(tmpDir, first); err == nil {
+		t.Error("expected dropping a rewritten-away commit to fail")
+	}
+
+	// A conflicting drop stops the rebase
+	commitFile("a.txt", "a changed\n", "change a")
+	commitFile("a.txt", "a changed again\n", "change a again")
+	if err := DropCommit(tmpDir, "HEAD~1"); err == nil {
+		t.Fatal("expected the conflicting drop to fail")
+	}
+	if !RebaseInProgress(tmpDir) {
+		t.Fatal("expected a rebase in progress after the conflict")
+	}
+	runGit("rebase", "--abort")
+	if RebaseInProgress(tmpDir) {
+		t.Error("expected no rebase in progress after abort")
+	}
+}
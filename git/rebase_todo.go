@@ -0,0 +1,125 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// RebaseActions are the todo actions offered for a commit, in git's order
+var RebaseActions = []string{"pick", "reword", "edit", "squash", "fixup", "drop"}
+
+// RebaseTodoItem is a line of an interactive rebase todo list
+type RebaseTodoItem struct {
+	Action  string // one of RebaseActions
+	Hash    string
+	Subject string
+}
+
+// GetRebaseTodo returns the commits from commit to HEAD, oldest first, all
+// picked, as git rebase -i would list them
+func GetRebaseTodo(repoRoot, commit string) ([]RebaseTodoItem, error) {
+	if err := CheckRewritable(repoRoot, commit); err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("git", "log", "--reverse", "--format=%H%x1f%s", "HEAD", "--not", commit+"^@")
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits after %s: %w", shortCommit(commit), err)
+	}
+
+	var items []RebaseTodoItem
+	for _, line := range strings.Split(string(output), "\n") {
+		hash, subject, ok := strings.Cut(line, "\x1f")
+		if !ok {
+			continue
+		}
+		items = append(items, RebaseTodoItem{Action: "pick", Hash: hash, Subject: subject})
+	}
+	return items, nil
+}
+
+// ValidateRebaseTodo checks the todo list before it is handed to git, which
+// would otherwise stop half-way with the rebase in progress
+func ValidateRebaseTodo(items []RebaseTodoItem) error {
+	picked := false
+	for _, item := range items {
+		switch item.Action {
+		case "pick", "reword", "edit":
+			picked = true
+		case "squash", "fixup":
+			if !picked {
+				return fmt.Errorf("cannot %s %s without a previous commit", item.Action, shortCommit(item.Hash))
+			}
+		case "drop":
+		default:
+			return fmt.Errorf("unknown rebase action %q", item.Action)
+		}
+	}
+	return nil
+}
+
+// RebaseTodoNeedsEditor reports whether git will ask for commit messages
+// while running the todo list (reword and squash)
+func RebaseTodoNeedsEditor(items []RebaseTodoItem) bool {
+	for _, item := range items {
+		if item.Action == "reword" || item.Action == "squash" {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatRebaseTodo writes the todo list in git-rebase-todo format
+func FormatRebaseTodo(items []RebaseTodoItem) string {
+	var sb strings.Builder
+	for _, item := range items {
+		sb.WriteString(item.Action + " " + item.Hash + " " + item.Subject + "\n")
+	}
+	return sb.String()
+}
+
+// RunRebaseTodo rebases the commits from commit to HEAD following the todo
+// list, which replaces the one git generates through GIT_SEQUENCE_EDITOR.
+// With useTerminal git runs on the terminal, so it can open the editor for
+// reword and squash messages; otherwise squash messages are combined as git
+// proposes them.
+func RunRebaseTodo(repoRoot, commit string, items []RebaseTodoItem, useTerminal bool) error {
+	if err := ValidateRebaseTodo(items); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", "giff-rebase-todo-*")
+	if err != nil {
+		return fmt.Errorf("failed to create todo file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(FormatRebaseTodo(items)); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write todo file: %w", err)
+	}
+	file.Close()
+
+	cmd := rebaseCommand(repoRoot, commit, "cp "+shellQuote(file.Name()))
+	if useTerminal {
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("git rebase failed: %w", err)
+		}
+		return nil
+	}
+	cmd.Env = append(cmd.Env, "GIT_EDITOR=true")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// shellQuote quotes s as a single shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,97 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestValidateRebaseTodo(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []string
+		wantErr bool
+	}{
+		{"all picked", []string{"pick", "pick"}, false},
+		{"squash after pick", []string{"pick", "squash", "fixup"}, false},
+		{"squash after reword", []string{"reword", "squash"}, false},
+		{"squash first", []string{"squash", "pick"}, true},
+		{"fixup after drop", []string{"drop", "fixup"}, true},
+		{"unknown action", []string{"pick", "merge"}, true},
+		{"everything dropped", []string{"drop", "drop"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]RebaseTodoItem, len(tt.actions))
+			for i, action := range tt.actions {
+				items[i] = RebaseTodoItem{Action: action, Hash: "0123456789abcdef"}
+			}
+			if err := ValidateRebaseTodo(items); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRebaseTodo(%v) error = %v, wantErr %v", tt.actions, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatRebaseTodo(t *testing.T) {
+	items := []RebaseTodoItem{
+		{Action: "pick", Hash: "aaa", Subject: "first"},
+		{Action: "fixup", Hash: "bbb", Subject: "second"},
+	}
+	want := "pick aaa first\nfixup bbb second\n"
+	if got := FormatRebaseTodo(items); got != want {
+		t.Errorf("FormatRebaseTodo() = %q, want %q", got, want)
+	}
+	if RebaseTodoNeedsEditor(items) {
+		t.Error("expected pick and fixup to need no editor")
+	}
+	items[1].Action = "squash"
+	if !RebaseTodoNeedsEditor(items) {
+		t.Error("expected squash to need an editor")
+	}
+}
+
+func TestRunRebaseTodo_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+
+	for _, subject := range []string{"first", "second", "third", "fourth"} {
+		runGit("commit", "--allow-empty", "-m", subject)
+	}
+
+	items, err := GetRebaseTodo(tmpDir, "HEAD~2")
+	if err != nil {
+		t.Fatalf("GetRebaseTodo failed: %v", err)
+	}
+	if len(items) != 3 || items[0].Subject != "second" || items[2].Subject != "fourth" || items[0].Action != "pick" {
+		t.Fatalf("unexpected todo: %+v", items)
+	}
+
+	// From the root commit
+	all, err := GetRebaseTodo(tmpDir, runGit("rev-list", "--max-parents=0", "HEAD"))
+	if err != nil || len(all) != 4 {
+		t.Fatalf("GetRebaseTodo from the root = %+v (err: %v)", all, err)
+	}
+
+	// Move fourth before second and fold third into second
+	items[0], items[2] = items[2], items[0]
+	items[1], items[2] = items[2], items[1]
+	items[2].Action = "fixup"
+	if err := RunRebaseTodo(tmpDir, "HEAD~2", items, false); err != nil {
+		t.Fatalf("RunRebaseTodo failed: %v", err)
+	}
+	if got := strings.ReplaceAll(runGit("log", "--format=%s"), "\n", ","); got != "second,fourth,first" {
+		t.Errorf("subjects after rebase = %q", got)
+	}
+
+	// An invalid todo is refused before git starts
+	items, _ = GetRebaseTodo(tmpDir, "HEAD~1")
+	items[0].Action = "squash"
+	if err := RunRebaseTodo(tmpDir, "HEAD~1", items, false); err == nil {
+		t.Error("expected squashing the first commit of the todo to fail")
+	}
+	if RebaseInProgress(tmpDir) {
+		t.Error("expected no rebase in progress after a refused todo")
+	}
+}
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ReflogEntry is a single entry of a reflog
+type ReflogEntry struct {
+	Selector string // e.g. "HEAD@{2}"
+	Hash     string // commit the ref pointed to after the action
+	Action   string // e.g. "commit (amend)", "checkout", "reset"
+	Message  string // e.g. "moving from main to feature"
+	Age      string // relative date of the action, e.g. "2 hours ago"
+}
+
+// ListReflog returns the reflog of ref ("HEAD" or a branch name), newest first
+func ListReflog(repoRoot, ref string) ([]ReflogEntry, error) {
+	// With --date=relative, %gd carries the time of the entry: HEAD@{2 hours ago}
+	cmd := exec.Command("git", "log", "--walk-reflogs", "--date=relative",
+		"--format=%gd%x1f%H%x1f%gs", ref, "--")
+	cmd.Dir = repoRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the reflog of %s: %s", ref, strings.TrimSpace(string(output)))
+	}
+	return parseReflog(ref, string(output)), nil
+}
+
+// parseReflog parses the output of ListReflog
+func parseReflog(ref, output string) []ReflogEntry {
+	var entries []ReflogEntry
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		age := fields[0]
+		if start := strings.Index(age, "@{"); start >= 0 && strings.HasSuffix(age, "}") {
+			age = age[start+2 : len(age)-1]
+		}
+		action, message, found := strings.Cut(fields[2], ": ")
+		if !found {
+			action, message = fields[2], ""
+		}
+		entries = append(entries, ReflogEntry{
+			Selector: fmt.Sprintf("%s@{%d}", ref, len(entries)),
+			Hash:     fields[1],
+			Action:   action,
+			Message:  message,
+			Age:      age,
+		})
+	}
+	return entries
+}
+
+// CheckoutDetached checks out commit with a detached HEAD
+func CheckoutDetached(repoRoot, commit string) error {
+	return runGit(repoRoot, "checkout", "--detach", commit)
+}
@@ -0,0 +1,58 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestParseReflog(t *testing.T) {
+	output := "HEAD@{2 minutes ago}\x1faaa\x1fcommit (amend): fix typo\n" +
+		"HEAD@{1 hour ago}\x1fbbb\x1fcheckout: moving from main to topic\n" +
+		"HEAD@{2 days ago}\x1fccc\x1finitial pull\n"
+
+	entries := parseReflog("HEAD", output)
+	want := []ReflogEntry{
+		{Selector: "HEAD@{0}", Hash: "aaa", Action: "commit (amend)", Message: "fix typo", Age: "2 minutes ago"},
+		{Selector: "HEAD@{1}", Hash: "bbb", Action: "checkout", Message: "moving from main to topic", Age: "1 hour ago"},
+		{Selector: "HEAD@{2}", Hash: "ccc", Action: "initial pull", Age: "2 days ago"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("parseReflog() returned %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestListReflog_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+
+	runGit("commit", "--allow-empty", "-m", "first")
+	lost := runGit("rev-parse", "HEAD")
+	runGit("commit", "--amend", "--allow-empty", "-m", "first, amended")
+
+	for _, ref := range []string{"HEAD", "main"} {
+		entries, err := ListReflog(tmpDir, ref)
+		if err != nil {
+			t.Fatalf("ListReflog(%s) failed: %v", ref, err)
+		}
+		if len(entries) != 2 || entries[0].Action != "commit (amend)" || entries[1].Hash != lost || entries[1].Selector != ref+"@{1}" {
+			t.Errorf("unexpected reflog of %s: %+v", ref, entries)
+		}
+	}
+
+	// The commit lost by the amend can be checked out again
+	if err := CheckoutDetached(tmpDir, lost); err != nil {
+		t.Fatalf("CheckoutDetached failed: %v", err)
+	}
+	if got := runGit("rev-parse", "HEAD"); got != lost {
+		t.Errorf("HEAD = %s, want %s", got, lost)
+	}
+
+	if _, err := ListReflog(tmpDir, "missing"); err == nil {
+		t.Error("expected an unknown ref to fail")
+	}
+}
@@ -0,0 +1,205 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// RemoteError is a failed fetch, pull or push. Error returns a readable
+// summary; Output keeps what git printed.
+type RemoteError struct {
+	Summary string
+	Output  string
+}
+
+func (e *RemoteError) Error() string {
+	return e.Summary
+}
+
+// Fetch fetches all remotes, writing git's progress to progress as it comes
+func Fetch(repoRoot string, progress io.Writer) error {
+	return runRemote(repoRoot, progress, "fetch", "fetch", "--all", "--progress")
+}
+
+// PullMode returns how Pull integrates the upstream of branch: "rebase" when
+// branch.<name>.rebase or pull.rebase is set, "ff-only" otherwise
+func PullMode(repoRoot, branch string) string {
+	for _, key := range []string{"branch." + branch + ".rebase", "pull.rebase"} {
+		value := strings.ToLower(configValue(repoRoot, key))
+		if value == "" {
+			continue
+		}
+		if value == "false" || value == "no" || value == "off" || value == "0" {
+			return "ff-only"
+		}
+		// true, merges and interactive all rebase
+		return "rebase"
+	}
+	return "ff-only"
+}
+
+// Pull fetches the upstream of the current branch and fast-forwards to it,
+// or rebases onto it when configured (see PullMode). A merge commit is never
+// created.
+func Pull(repoRoot string, progress io.Writer) error {
+	head, err := GetHeadStatus(repoRoot)
+	if err != nil {
+		return err
+	}
+	if head.Branch == "" {
+		return &RemoteError{Summary: "HEAD is detached: check out a branch to pull"}
+	}
+	if head.Upstream == "" {
+		return &RemoteError{Summary: "Branch " + head.Branch + " has no upstream: push it first to set one"}
+	}
+	return runRemote(repoRoot, progress, "pull", "pull", "--"+PullMode(repoRoot, head.Branch), "--progress")
+}
+
+// Push pushes the current branch to its upstream. A branch without upstream
+// is pushed to the push remote under the same name and tracks it from then on.
+func Push(repoRoot string, progress io.Writer) error {
+	head, err := GetHeadStatus(repoRoot)
+	if err != nil {
+		return err
+	}
+	if head.Branch == "" {
+		return &RemoteError{Summary: "HEAD is detached: check out a branch to push"}
+	}
+	if head.Upstream != "" && !head.UpstreamGone {
+		return runRemote(repoRoot, progress, "push", "push", "--progress")
+	}
+	remote, err := pushRemote(repoRoot, head.Branch)
+	if err != nil {
+		return err
+	}
+	return runRemote(repoRoot, progress, "push", "push", "--progress", "--set-upstream", remote, head.Branch)
+}
+
+// pushRemote returns the remote a branch without upstream is pushed to:
+// branch.<name>.pushRemote, remote.pushDefault, origin or the only remote
+func pushRemote(repoRoot, branch string) (string, error) {
+	for _, key := range []string{"branch." + branch + ".pushRemote", "remote.pushDefault"} {
+		if remote := configValue(repoRoot, key); remote != "" {
+			return remote, nil
+		}
+	}
+	cmd := exec.Command("git", "remote")
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list remotes: %w", err)
+	}
+	remotes := strings.Fields(string(output))
+	for _, remote := range remotes {
+		if remote == "origin" {
+			return remote, nil
+		}
+	}
+	if len(remotes) == 1 {
+		return remotes[0], nil
+	}
+	if len(remotes) == 0 {
+		return "", &RemoteError{Summary: "No remote is configured: add one with git remote add"}
+	}
+	return "", &RemoteError{Summary: "Several remotes and none is origin: set remote.pushDefault"}
+}
+
+// configValue returns the value of a git config key, or "" when unset.
+// Options such as --path or --type=bool go before --get.
+func configValue(repoRoot, key string, options ...string) string {
+	args := append(append([]string{"config"}, options...), "--get", key)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// runRemote runs a git command that talks to a remote, streaming its output
+// to progress. Git must not ask for credentials on the terminal the UI is
+// drawn on, so prompts are disabled and fail as authentication errors.
+func runRemote(repoRoot string, progress io.Writer, name string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if os.Getenv("GIT_SSH_COMMAND") == "" && configValue(repoRoot, "core.sshCommand") == "" {
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+	}
+
+	var output bytes.Buffer
+	writer := io.Writer(&output)
+	if progress != nil {
+		writer = io.MultiWriter(&output, progress)
+	}
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if err := cmd.Run(); err != nil {
+		return &RemoteError{
+			Summary: summarizeRemoteError(name, output.String()),
+			Output:  output.String(),
+		}
+	}
+	return nil
+}
+
+// summarizeRemoteError turns the output of a failed fetch, pull or push into
+// a one-line explanation
+func summarizeRemoteError(name, output string) string {
+	lower := strings.ToLower(output)
+	switch {
+	case strings.Contains(lower, "authentication failed"),
+		strings.Contains(lower, "could not read username"),
+		strings.Contains(lower, "could not read password"),
+		strings.Contains(lower, "permission denied"),
+		strings.Contains(lower, "terminal prompts disabled"),
+		strings.Contains(lower, "host key verification failed"):
+		return "Authentication failed: check your credentials or SSH key for the remote"
+	case strings.Contains(lower, "[rejected]") && (strings.Contains(lower, "fetch first") || strings.Contains(lower, "non-fast-forward")):
+		return "Push rejected: the remote has commits you don't have, pull first"
+	case strings.Contains(lower, "[remote rejected]"):
+		return "Push rejected by the remote: " + rejectionReason(output)
+	case strings.Contains(lower, "not possible to fast-forward"):
+		return "Cannot fast-forward: the branch and its upstream have diverged, rebase or merge them"
+	case strings.Contains(lower, "would be overwritten"),
+		strings.Contains(lower, "cannot pull with rebase"),
+		strings.Contains(lower, "you have unstaged changes"):
+		return "Pull would overwrite local changes: commit or stash them first"
+	case strings.Contains(lower, "conflict"):
+		return "Pull stopped on conflicts: resolve them, then press C to continue"
+	case strings.Contains(lower, "does not appear to be a git repository"),
+		strings.Contains(lower, "could not resolve host"),
+		strings.Contains(lower, "could not read from remote repository"),
+		strings.Contains(lower, "unable to access"):
+		return "Cannot reach the remote: check its URL and your network"
+	}
+	return "git " + name + " failed: " + lastLine(output)
+}
+
+// rejectionReason returns the reason given in a "[remote rejected] ... (reason)" line
+func rejectionReason(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if _, rest, ok := strings.Cut(line, "[remote rejected]"); ok {
+			if start := strings.LastIndex(rest, "("); start >= 0 {
+				return strings.TrimSuffix(strings.TrimSpace(rest[start+1:]), ")")
+			}
+		}
+	}
+	return lastLine(output)
+}
+
+// lastLine returns the last non-empty line of output
+func lastLine(output string) string {
+	lines := strings.FieldsFunc(output, func(r rune) bool { return r == '\n' || r == '\r' })
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			return line
+		}
+	}
+	return "no output"
+}
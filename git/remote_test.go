@@ -0,0 +1,133 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestSummarizeRemoteError(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{
+			output: "fatal: could not read Username for 'https://example.com': terminal prompts disabled",
+			want:   "Authentication failed",
+		},
+		{
+			output: " ! [rejected]        main -> main (fetch first)\nerror: failed to push some refs",
+			want:   "Push rejected: the remote has commits",
+		},
+		{
+			output: " ! [remote rejected] main -> main (pre-receive hook declined)\nerror: failed to push some refs",
+			want:   "Push rejected by the remote: pre-receive hook declined",
+		},
+		{
+			output: "hint: Diverging branches can't be fast-forwarded\nfatal: Not possible to fast-forward, aborting.",
+			want:   "Cannot fast-forward",
+		},
+		{
+			output: "fatal: 'nowhere' does not appear to be a git repository",
+			want:   "Cannot reach the remote",
+		},
+		{
+			output: "something unexpected\nfatal: the last line\n",
+			want:   "git push failed: fatal: the last line",
+		},
+	}
+	for _, tt := range tests {
+		if got := summarizeRemoteError("push", tt.output); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("summarizeRemoteError(%q) = %q, want prefix %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestRemote_Integration(t *testing.T) {
+	local, runLocal := gittest.NewTestRepo(t)
+	other, runOther := gittest.NewTestRepo(t)
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	commitFile := func(dir string, run func(...string) string, name, content, message string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", name)
+		run("commit", "-m", message)
+	}
+
+	runLocal("init", "--bare", "-b", "main", remote)
+	commitFile(local, runLocal, "a.txt", "a\n", "first")
+
+	// Without a remote there is nowhere to push
+	var remoteErr *RemoteError
+	if err := Push(local, nil); !errors.As(err, &remoteErr) {
+		t.Fatalf("Push without remote = %v, want a RemoteError", err)
+	}
+	runLocal("remote", "add", "origin", remote)
+
+	// First push sets the upstream
+	var progress bytes.Buffer
+	if err := Push(local, &progress); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if !strings.Contains(progress.String(), "main -> main") {
+		t.Errorf("expected push progress to be streamed, got %q", progress.String())
+	}
+	if got := runLocal("rev-parse", "--abbrev-ref", "main@{upstream}"); got != "origin/main" {
+		t.Errorf("upstream after first push = %q, want origin/main", got)
+	}
+
+	// Another clone pushes a commit
+	runOther("remote", "add", "origin", remote)
+	runOther("pull", "origin", "main")
+	runOther("branch", "--set-upstream-to=origin/main")
+	commitFile(other, runOther, "b.txt", "b\n", "from other")
+	runOther("push")
+
+	if err := Fetch(local, nil); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if status, _ := GetHeadStatus(local); status.Behind != 1 {
+		t.Errorf("behind after fetch = %d, want 1", status.Behind)
+	}
+	if err := Pull(local, nil); err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+	if got := runLocal("log", "-1", "--format=%s"); got != "from other" {
+		t.Errorf("HEAD after pull = %q, want %q", got, "from other")
+	}
+
+	// Diverged: the push is rejected and a fast-forward pull is impossible
+	commitFile(other, runOther, "c.txt", "c\n", "other again")
+	runOther("push")
+	commitFile(local, runLocal, "d.txt", "d\n", "local change")
+	err := Push(local, nil)
+	if !errors.As(err, &remoteErr) || !strings.HasPrefix(remoteErr.Summary, "Push rejected") || remoteErr.Output == "" {
+		t.Errorf("Push of a diverged branch = %v, want a rejection", err)
+	}
+	err = Pull(local, nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "Cannot fast-forward") {
+		t.Errorf("ff-only Pull of a diverged branch = %v, want a fast-forward error", err)
+	}
+
+	// With pull.rebase the local commit is replayed on the upstream
+	runLocal("config", "pull.rebase", "true")
+	if mode := PullMode(local, "main"); mode != "rebase" {
+		t.Errorf("PullMode() = %q, want rebase", mode)
+	}
+	if err := Pull(local, nil); err != nil {
+		t.Fatalf("rebase Pull failed: %v", err)
+	}
+	if got := runLocal("log", "-2", "--format=%s"); got != "local change\nother again" {
+		t.Errorf("history after rebase pull = %q", got)
+	}
+	if err := Push(local, nil); err != nil {
+		t.Fatalf("Push after rebase failed: %v", err)
+	}
+}
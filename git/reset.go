@@ -0,0 +1,33 @@
+package git
+
+// ResetMode selects what Reset moves besides HEAD
+type ResetMode int
+
+const (
+	ResetSoft  ResetMode = iota // only HEAD
+	ResetMixed                  // HEAD and the index
+	ResetHard                   // HEAD, the index and the working tree
+)
+
+// String returns the git reset flag name of the mode
+func (m ResetMode) String() string {
+	switch m {
+	case ResetSoft:
+		return "soft"
+	case ResetHard:
+		return "hard"
+	default:
+		return "mixed"
+	}
+}
+
+// Reset moves HEAD (and the current branch) to commit
+func Reset(repoRoot, commit string, mode ResetMode) error {
+	return runGit(repoRoot, "reset", "--"+mode.String(), commit)
+}
+
+// RestoreFileFromCommit overwrites path in the working tree with its content
+// in commit; the index is left alone
+func RestoreFileFromCommit(repoRoot, commit, path string) error {
+	return runGit(repoRoot, "restore", "--source="+commit, "--worktree", "--", path)
+}
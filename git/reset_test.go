@@ -0,0 +1,104 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestCherryPickRevertResetRestore_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	commitFile := func(name, content, message string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit("add", name)
+		runGit("commit", "-m", message)
+		return runGit("rev-parse", "HEAD")
+	}
+	readFile := func(name string) string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	base := commitFile("a.txt", "a\n", "base")
+	runGit("checkout", "-b", "topic")
+	picked := commitFile("b.txt", "b\n", "add b")
+	runGit("checkout", "main")
+
+	// Cherry-pick
+	if err := CherryPick(tmpDir, picked); err != nil {
+		t.Fatalf("CherryPick failed: %v", err)
+	}
+	if got := runGit("log", "-1", "--format=%s"); got != "add b" {
+		t.Errorf("HEAD after cherry-pick = %q, want %q", got, "add b")
+	}
+
+	// Revert
+	if err := Revert(tmpDir, "HEAD"); err != nil {
+		t.Fatalf("Revert failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "b.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected b.txt to be removed by the revert, got %v", err)
+	}
+
+	// Reset modes
+	head := runGit("rev-parse", "HEAD")
+	if err := Reset(tmpDir, base, ResetSoft); err != nil {
+		t.Fatalf("Reset soft failed: %v", err)
+	}
+	if got := runGit("diff", "--cached", "--name-only"); got != "" {
+		t.Errorf("staged files after soft reset over a pick and its revert = %q, want none", got)
+	}
+	if err := Reset(tmpDir, head, ResetMixed); err != nil {
+		t.Fatalf("Reset mixed failed: %v", err)
+	}
+	commitFile("a.txt", "a changed\n", "change a")
+	if err := Reset(tmpDir, base, ResetMixed); err != nil {
+		t.Fatalf("Reset mixed failed: %v", err)
+	}
+	if got := runGit("diff", "--name-only"); got != "a.txt" {
+		t.Errorf("unstaged files after mixed reset = %q, want a.txt", got)
+	}
+	if err := Reset(tmpDir, base, ResetHard); err != nil {
+		t.Fatalf("Reset hard failed: %v", err)
+	}
+	if got := readFile("a.txt"); got != "a\n" {
+		t.Errorf("a.txt after hard reset = %q", got)
+	}
+
+	// Restore a file into the working tree only
+	if err := RestoreFileFromCommit(tmpDir, picked, "b.txt"); err != nil {
+		t.Fatalf("RestoreFileFromCommit failed: %v", err)
+	}
+	if got := readFile("b.txt"); got != "b\n" {
+		t.Errorf("restored b.txt = %q", got)
+	}
+	if got := runGit("diff", "--cached", "--name-only"); got != "" {
+		t.Errorf("expected the index to be left alone, got staged %q", got)
+	}
+	if err := RestoreFileFromCommit(tmpDir, base, "missing.txt"); err == nil {
+		t.Error("expected restoring a path missing from the commit to fail")
+	}
+	runGit("clean", "-fd")
+
+	// A conflicting cherry-pick stops
+	commitFile("b.txt", "other b\n", "conflicting b")
+	if err := CherryPick(tmpDir, picked); err == nil {
+		t.Fatal("expected the conflicting cherry-pick to fail")
+	}
+	if !CherryPickInProgress(tmpDir) || RevertInProgress(tmpDir) {
+		t.Error("expected a cherry-pick in progress")
+	}
+	runGit("cherry-pick", "--abort")
+	if CherryPickInProgress(tmpDir) {
+		t.Error("expected no cherry-pick in progress after abort")
+	}
+}
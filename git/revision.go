@@ -0,0 +1,247 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// RevisionRange describes a comparison given on the command line,
+// using the same notation as `git diff`:
+//
+//	<rev>          compare <rev> with the working tree
+//	<rev1>..<rev2> compare <rev1> with <rev2>
+//	<rev1>...<rev2> compare the merge-base of <rev1> and <rev2> with <rev2>
+type RevisionRange struct {
+	From      string
+	To        string // empty = working tree (or the index, see Index)
+	MergeBase bool   // true for "<rev1>...<rev2>"
+	Index     bool   // compare From with the index instead of the working tree
+}
+
+// ParseRevisionRange parses a revision or range argument
+func ParseRevisionRange(spec string) RevisionRange {
+	if from, to, ok := strings.Cut(spec, "..."); ok {
+		return RevisionRange{From: defaultRevision(from), To: defaultRevision(to), MergeBase: true}
+	}
+	if from, to, ok := strings.Cut(spec, ".."); ok {
+		return RevisionRange{From: defaultRevision(from), To: defaultRevision(to)}
+	}
+	return RevisionRange{From: spec}
+}
+
+// defaultRevision fills an omitted side of a range with HEAD, like git does
+func defaultRevision(rev string) string {
+	if rev == "" {
+		return "HEAD"
+	}
+	return rev
+}
+
+// String returns the range in git notation
+func (r RevisionRange) String() string {
+	switch {
+	case r.To == "" && r.Index:
+		return "--cached " + r.From
+	case r.To == "":
+		return r.From
+	case r.MergeBase:
+		return r.From + "..." + r.To
+	default:
+		return r.From + ".." + r.To
+	}
+}
+
+// diffArgs returns the revision arguments passed to git diff
+func (r RevisionRange) diffArgs() []string {
+	if r.To == "" && r.Index {
+		return []string{"--cached", r.From}
+	}
+	if r.To == "" {
+		return []string{r.From}
+	}
+	return []string{r.String()}
+}
+
+// ValidateRevisionRange checks that every revision in the range exists
+func ValidateRevisionRange(r RevisionRange, repoRoot string) error {
+	for _, rev := range []string{r.From, r.To} {
+		if rev == "" {
+			continue
+		}
+		cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+		cmd.Dir = repoRoot
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("unknown revision: %s", rev)
+		}
+	}
+	return nil
+}
+
+// GetRevisionChangedFiles returns the files that differ in the given range
+func GetRevisionChangedFiles(r RevisionRange, repoRoot string) ([]FileInfo, error) {
+	// -z keeps file names verbatim (no quoting or escaping)
+	args := append([]string{"diff", "--name-status", "--no-renames", "-z"}, r.diffArgs()...)
+	args = append(args, "--")
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute git diff --name-status: %w", err)
+	}
+
+	// Output is a sequence of <status>\0<path>\0
+	var files []FileInfo
+	fields := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		status := fields[i]
+		path := fields[i+1]
+		if status == "" || path == "" {
+			continue
+		}
+		var changeStatus string
+		switch status[0] {
+		case 'A':
+			changeStatus = "added"
+		case 'D':
+			changeStatus = "deleted"
+		default:
+			changeStatus = "modified"
+		}
+		files = append(files, FileInfo{Path: path, ChangeStatus: changeStatus})
+	}
+	return files, nil
+}
+
+// GetRevisionDiffWithOptions returns the diff of a single file in the given range
+func GetRevisionDiffWithOptions(filePath string, repoRoot string, r RevisionRange, ignoreWhitespace bool) (string, error) {
+	args := []string{"-c", "core.quotepath=false", "diff"}
+	if ignoreWhitespace {
+		args = append(args, "-w")
+	}
+	args = append(args, r.diffArgs()...)
+	args = append(args, "--", filePath)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to execute git diff %s: %w", r, err)
+	}
+	return string(output), nil
+}
+
+// GetMergeChangedFiles returns the files a merge commit changed relative to
+// all of its parents (the files in its combined diff)
+func GetMergeChangedFiles(hash string, repoRoot string) ([]FileInfo, error) {
+	cmd := exec.Command("git", "-c", "core.quotepath=false", "diff-tree", "--cc", "--name-status", "-r", "--no-commit-id", hash)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute git diff-tree --cc: %w", err)
+	}
+
+	// One <status per parent>\t<path> line per file
+	var files []FileInfo
+	for _, line := range strings.Split(string(output), "\n") {
+		status, path, ok := strings.Cut(line, "\t")
+		if !ok || path == "" {
+			continue
+		}
+		changeStatus := "modified"
+		if strings.Trim(status, "A") == "" {
+			changeStatus = "added"
+		} else if strings.Trim(status, "D") == "" {
+			changeStatus = "deleted"
+		}
+		files = append(files, FileInfo{Path: path, ChangeStatus: changeStatus})
+	}
+	return files, nil
+}
+
+// GetCombinedDiffWithOptions returns the combined diff of a file in a merge
+// commit, converted to the unified format (see combinedToUnified)
+func GetCombinedDiffWithOptions(filePath string, repoRoot string, hash string, ignoreWhitespace bool) (string, error) {
+	args := []string{"-c", "core.quotepath=false", "show", "--cc", "--format="}
+	if ignoreWhitespace {
+		args = append(args, "-w")
+	}
+	args = append(args, hash, "--", filePath)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to execute git show --cc %s: %w", hash, err)
+	}
+	return combinedToUnified(strings.TrimLeft(string(output), "\n")), nil
+}
+
+// combinedToUnified converts a combined diff (one marker column per parent)
+// to the unified format the diff views render, as seen from the first parent:
+// its column gives the marker, and lines only other parents had are left out,
+// so the old side counts and numbers follow the first parent.
+func combinedToUnified(diffText string) string {
+	if diffText == "" {
+		return ""
+	}
+	var out []string
+	var hunk []string
+	var oldStart, newStart, parents int
+	inHunk := false
+
+	flush := func() {
+		if !inHunk {
+			return
+		}
+		oldCount, newCount := 0, 0
+		for _, line := range hunk {
+			switch {
+			case strings.HasPrefix(line, "-"):
+				oldCount++
+			case strings.HasPrefix(line, "+"):
+				newCount++
+			case strings.HasPrefix(line, " "):
+				oldCount++
+				newCount++
+			}
+		}
+		out = append(out, fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, oldCount, newStart, newCount))
+		out = append(out, hunk...)
+		hunk = nil
+		inHunk = false
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(diffText, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@@"):
+			flush()
+			// @@@ -<first parent> -<second parent> +<result> @@@
+			parents = len(line) - len(strings.TrimLeft(line, "@")) - 1
+			fields := strings.Fields(line)
+			if len(fields) < parents+2 {
+				continue
+			}
+			fmt.Sscanf(fields[1], "-%d", &oldStart)
+			fmt.Sscanf(fields[parents+1], "+%d", &newStart)
+			inHunk = true
+		case strings.HasPrefix(line, "diff --cc "):
+			flush()
+			path := strings.TrimPrefix(line, "diff --cc ")
+			out = append(out, "diff --git a/"+path+" b/"+path)
+		case inHunk && strings.HasPrefix(line, "\\"):
+			// "\ No newline at end of file"
+			hunk = append(hunk, line)
+		case inHunk && len(line) >= parents:
+			marks, text := line[:parents], line[parents:]
+			if marks[0] == ' ' && strings.Contains(marks, "-") {
+				// Removed from another parent: not in the first parent nor the result
+				continue
+			}
+			hunk = append(hunk, marks[:1]+text)
+		default:
+			out = append(out, line)
+		}
+	}
+	flush()
+	return strings.Join(out, "\n") + "\n"
+}
@@ -0,0 +1,214 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestParseRevisionRange(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected RevisionRange
+	}{
+		{"main", RevisionRange{From: "main"}},
+		{"HEAD~3", RevisionRange{From: "HEAD~3"}},
+		{"main..feature", RevisionRange{From: "main", To: "feature"}},
+		{"main..", RevisionRange{From: "main", To: "HEAD"}},
+		{"..feature", RevisionRange{From: "HEAD", To: "feature"}},
+		{"main...feature", RevisionRange{From: "main", To: "feature", MergeBase: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got := ParseRevisionRange(tt.spec)
+			if got != tt.expected {
+				t.Errorf("ParseRevisionRange(%q) = %+v, want %+v", tt.spec, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRevisionRangeString(t *testing.T) {
+	tests := []struct {
+		r        RevisionRange
+		expected string
+	}{
+		{RevisionRange{From: "main"}, "main"},
+		{RevisionRange{From: "main", To: "feature"}, "main..feature"},
+		{RevisionRange{From: "main", To: "feature", MergeBase: true}, "main...feature"},
+		{RevisionRange{From: "main", Index: true}, "--cached main"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.r.String(); got != tt.expected {
+			t.Errorf("String() = %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+func TestCombinedToUnified(t *testing.T) {
+	combined := `diff --cc f
+index 6dcce7d,7be73ce..6e5aa5d
+--- a/f
++++ b/f
+@@@ -1,3 -1,3 +1,3 @@@
+  a
+- b
+ -B
+ -c
+++B2
+ +C
+`
+	want := `diff --git a/f b/f
+index 6dcce7d,7be73ce..6e5aa5d
+--- a/f
++++ b/f
+@@ -1,3 +1,3 @@
+ a
+-b
++B2
+ C
+`
+	if got := combinedToUnified(combined); got != want {
+		t.Errorf("combinedToUnified() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := combinedToUnified(""); got != "" {
+		t.Errorf("combinedToUnified(\"\") = %q, want empty", got)
+	}
+}
+
+func TestGetRevisionChanges_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("a.txt", "a1\n")
+	writeFile("b.txt", "b\n")
+	runGit("add", ".")
+	runGit("commit", "-m", "first")
+	runGit("tag", "v1")
+
+	writeFile("a.txt", "a2\n")
+	writeFile("c.txt", "c\n")
+	runGit("rm", "-q", "b.txt")
+	runGit("add", ".")
+	runGit("commit", "-m", "second")
+
+	// Uncommitted change: only a single revision compares the working tree
+	writeFile("c.txt", "c2\n")
+
+	tests := []struct {
+		spec      string
+		wantFiles []FileInfo
+		wantC     string // added line of c.txt in the diff
+	}{
+		{
+			spec: "v1",
+			wantFiles: []FileInfo{
+				{Path: "a.txt", ChangeStatus: "modified"},
+				{Path: "b.txt", ChangeStatus: "deleted"},
+				{Path: "c.txt", ChangeStatus: "added"},
+			},
+			wantC: "+c2\n",
+		},
+		{
+			spec: "v1..HEAD",
+			wantFiles: []FileInfo{
+				{Path: "a.txt", ChangeStatus: "modified"},
+				{Path: "b.txt", ChangeStatus: "deleted"},
+				{Path: "c.txt", ChangeStatus: "added"},
+			},
+			wantC: "+c\n",
+		},
+		{
+			spec:      "HEAD~1..v1",
+			wantFiles: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			r := ParseRevisionRange(tt.spec)
+			if err := ValidateRevisionRange(r, tmpDir); err != nil {
+				t.Fatalf("ValidateRevisionRange() error = %v", err)
+			}
+			files, err := GetRevisionChangedFiles(r, tmpDir)
+			if err != nil {
+				t.Fatalf("GetRevisionChangedFiles() error = %v", err)
+			}
+			if !reflect.DeepEqual(files, tt.wantFiles) {
+				t.Errorf("GetRevisionChangedFiles() = %+v, want %+v", files, tt.wantFiles)
+			}
+			if tt.wantC == "" {
+				return
+			}
+
+			diff, err := GetRevisionDiffWithOptions("c.txt", tmpDir, r, false)
+			if err != nil {
+				t.Fatalf("GetRevisionDiffWithOptions() error = %v", err)
+			}
+			if !strings.Contains(diff, "new file mode") || !strings.HasSuffix(diff, "@@ -0,0 +1 @@\n"+tt.wantC) {
+				t.Errorf("GetRevisionDiffWithOptions(c.txt) =\n%s\nwant c.txt added with %q", diff, tt.wantC)
+			}
+			diff, err = GetRevisionDiffWithOptions("a.txt", tmpDir, r, false)
+			if err != nil {
+				t.Fatalf("GetRevisionDiffWithOptions() error = %v", err)
+			}
+			if !strings.HasSuffix(diff, "-a1\n+a2\n") {
+				t.Errorf("GetRevisionDiffWithOptions(a.txt) =\n%s", diff)
+			}
+		})
+	}
+
+	if err := ValidateRevisionRange(ParseRevisionRange("v1..nope"), tmpDir); err == nil {
+		t.Error("expected an unknown revision to fail validation")
+	}
+}
+
+func TestGetCombinedDiff_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	writeFile := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, "f.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("a\nbase\nc\n")
+	runGit("add", "f.txt")
+	runGit("commit", "-m", "base")
+	runGit("checkout", "-q", "-b", "feature")
+	writeFile("a\nfeature\nc\n")
+	runGit("commit", "-am", "feature")
+	runGit("checkout", "-q", "main")
+	writeFile("a\nmain\nc\n")
+	runGit("commit", "-am", "main")
+
+	// The resolution keeps the main line (context against the first parent,
+	// added against the second), drops the feature line and adds its own
+	cmd := exec.Command("git", "merge", "feature")
+	cmd.Dir = tmpDir
+	cmd.Run()
+	writeFile("a\nmain\nresolved\nc\n")
+	runGit("commit", "-am", "merge")
+
+	diff, err := GetCombinedDiffWithOptions("f.txt", tmpDir, "HEAD", false)
+	if err != nil {
+		t.Fatalf("GetCombinedDiffWithOptions() error = %v", err)
+	}
+	want := "@@ -1,3 +1,4 @@\n a\n main\n+resolved\n c\n"
+	if !strings.HasSuffix(diff, want) {
+		t.Errorf("GetCombinedDiffWithOptions() =\n%s\nwant it to end with\n%s", diff, want)
+	}
+}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
@@ -58,11 +59,96 @@ func GetFileContentFromHEAD(filePath string, repoRoot string) ([]byte, error) {
 	return output, nil
 }
 
+// GetIndexFileMode returns the mode recorded in the index for filePath (e.g. "100644")
+func GetIndexFileMode(filePath string, repoRoot string) (string, error) {
+	cmd := exec.Command("git", "-c", "core.quotepath=false", "ls-files", "--stage", "--", filePath)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to execute git ls-files: %w", err)
+	}
+
+	// Format: <mode> <object> <stage>\t<path>
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s is not in the index", filePath)
+	}
+	return fields[0], nil
+}
+
+// workingTreeFileMode returns the git file mode matching the working tree file
+func workingTreeFileMode(filePath string, repoRoot string) (string, error) {
+	info, err := os.Lstat(filepath.Join(repoRoot, filePath))
+	if err != nil {
+		return "", err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return "120000", nil
+	}
+	if info.Mode()&0111 != 0 {
+		return "100755", nil
+	}
+	return "100644", nil
+}
+
+// WriteBlob stores content in the object database and returns its hash
+func WriteBlob(content []byte, repoRoot string) (string, error) {
+	cmd := exec.Command("git", "hash-object", "-w", "--stdin")
+	cmd.Dir = repoRoot
+	cmd.Stdin = bytes.NewReader(content)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to execute git hash-object: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// StageContent records content as the staged version of filePath.
+// The blob is written directly to the object database and the index entry is
+// updated in place, so the working tree file and its mode are never touched.
+func StageContent(filePath string, repoRoot string, content string) error {
+	// Keep the mode already in the index; fall back to the working tree for new entries
+	mode, err := GetIndexFileMode(filePath, repoRoot)
+	if err != nil {
+		mode, err = workingTreeFileMode(filePath, repoRoot)
+		if err != nil {
+			return fmt.Errorf("failed to determine file mode: %w", err)
+		}
+	}
+
+	hash, err := WriteBlob([]byte(content), repoRoot)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("git", "-c", "core.quotepath=false", "update-index", "--add", "--cacheinfo", fmt.Sprintf("%s,%s,%s", mode, hash, filePath))
+	cmd.Dir = repoRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to update index: %s", string(output))
+	}
+	return nil
+}
+
+// AddIntentToAdd registers an untracked file in the index with empty content
+// (git add --intent-to-add), so its lines can be staged selectively
+func AddIntentToAdd(filePath string, repoRoot string) error {
+	cmd := exec.Command("git", "-c", "core.quotepath=false", "add", "--intent-to-add", "--", filePath)
+	cmd.Dir = repoRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to add %s with intent-to-add: %s", filePath, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 // RevertSelectedChangesFromStaged returns file content with selected changes reverted from staging
 // It takes the staged diff and reverts only the selected lines back to HEAD state
 func RevertSelectedChangesFromStaged(filePath string, repoRoot string, stagedDiffText string, selectedStart, selectedEnd int) (string, error) {
-	// Get HEAD content
-	headContent, err := GetFileContentFromHEAD(filePath, repoRoot)
+	// Get HEAD content (of the original path, which the diff of a rename is against)
+	headPath := filePath
+	if origPath := stagedRenameSource(filePath, repoRoot); origPath != "" {
+		headPath = origPath
+	}
+	headContent, err := GetFileContentFromHEAD(headPath, repoRoot)
 	if err != nil {
 		return "", fmt.Errorf("failed to get HEAD content: %w", err)
 	}
@@ -77,6 +163,33 @@ func RevertSelectedChangesFromStaged(filePath string, repoRoot string, stagedDif
 	return result, nil
 }
 
+// RevertSelectedChangesFromWorkingTree returns working tree content with selected changes reverted
+// It takes the unstaged diff and reverts only the selected lines back to the index state
+func RevertSelectedChangesFromWorkingTree(filePath string, repoRoot string, diffText string, selectedStart, selectedEnd int) (string, error) {
+	// Get the index content (falls back to HEAD)
+	indexContent, err := GetFileContentFromIndex(filePath, repoRoot)
+	if err != nil {
+		return "", fmt.Errorf("failed to get staged version: %w", err)
+	}
+
+	// Apply only NON-selected changes on top of the index content
+	diffLines := strings.Split(diffText, "\n")
+	return applyNonSelectedDiffLines(string(indexContent), diffLines, selectedStart, selectedEnd), nil
+}
+
+// WriteWorkingTreeContent replaces the content of a working tree file, keeping its mode
+func WriteWorkingTreeContent(filePath string, repoRoot string, content string) error {
+	fullPath := filepath.Join(repoRoot, filePath)
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	if err := os.WriteFile(fullPath, []byte(content), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
 // applyNonSelectedDiffLines applies diff lines EXCEPT the selected ones to base content
 // This effectively reverts the selected changes
 func applyNonSelectedDiffLines(baseContent string, diffLines []string, selectedStart, selectedEnd int) string {
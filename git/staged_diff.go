@@ -3,6 +3,7 @@ package git
 import (
 	"fmt"
 	"os/exec"
+	"strings"
 )
 
 func GetStagedDiff(filePath string, repoRoot string) (string, error) {
@@ -16,7 +17,12 @@ func GetStagedDiffWithOptions(filePath string, repoRoot string, ignoreWhitespace
 	if ignoreWhitespace {
 		args = append(args, "-w")
 	}
-	args = append(args, "--", filePath)
+	// A rename is only detected when both paths are given
+	if origPath := stagedRenameSource(filePath, repoRoot); origPath != "" {
+		args = append(args, "-M", "--", origPath, filePath)
+	} else {
+		args = append(args, "--", filePath)
+	}
 
 	cmd := exec.Command("git", args...)
 	cmd.Dir = repoRoot
@@ -28,3 +34,23 @@ func GetStagedDiffWithOptions(filePath string, repoRoot string, ignoreWhitespace
 	// Return the diff
 	return string(output), nil
 }
+
+// stagedRenameSource returns the path a staged file was renamed from, or ""
+// when it is not a staged rename
+func stagedRenameSource(filePath string, repoRoot string) string {
+	cmd := exec.Command("git", "diff", "--cached", "-M", "--name-status", "-z", "--diff-filter=R")
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	// Records are "R<score>", the old path and the new path
+	records := strings.Split(string(output), "\x00")
+	for i := 0; i+2 < len(records); i += 3 {
+		if records[i+2] == filePath {
+			return records[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestGetStagedDiff_Rename(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+
+	content := "one\ntwo\nthree\nfour\nfive\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "old.txt"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit("add", "old.txt")
+	runGit("commit", "-m", "initial")
+
+	runGit("mv", "old.txt", "new.txt")
+	if err := os.WriteFile(filepath.Join(tmpDir, "new.txt"), []byte(content+"six\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit("add", "new.txt")
+
+	diffText, err := GetStagedDiff("new.txt", tmpDir)
+	if err != nil {
+		t.Fatalf("GetStagedDiff failed: %v", err)
+	}
+	for _, want := range []string{"rename from old.txt", "rename to new.txt", "+six"} {
+		if !strings.Contains(diffText, want) {
+			t.Errorf("staged diff does not contain %q:\n%s", want, diffText)
+		}
+	}
+	if strings.Contains(diffText, "+one") {
+		t.Errorf("renamed file is shown as added:\n%s", diffText)
+	}
+
+	// Unstaging the added line keeps the rename staged
+	lines := strings.Split(diffText, "\n")
+	sixIdx := -1
+	for i, line := range lines {
+		if line == "+six" {
+			sixIdx = i
+		}
+	}
+	reverted, err := RevertSelectedChangesFromStaged("new.txt", tmpDir, diffText, sixIdx, sixIdx)
+	if err != nil {
+		t.Fatalf("RevertSelectedChangesFromStaged failed: %v", err)
+	}
+	if reverted != "one\ntwo\nthree\nfour\nfive\n" {
+		t.Errorf("content with the added line unstaged = %q", reverted)
+	}
+}
@@ -0,0 +1,160 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// StashEntry is a single entry of `git stash list`
+type StashEntry struct {
+	Ref     string // e.g. "stash@{0}"
+	Hash    string
+	Message string
+	Age     string // relative date, e.g. "2 hours ago"
+}
+
+// StashMode selects which changes StashPush stashes
+type StashMode int
+
+const (
+	StashAll    StashMode = iota // all changes, including untracked files
+	StashStaged                  // only the staged changes
+	StashPaths                   // only the given paths
+)
+
+// ListStashes returns the stash entries, newest first
+func ListStashes(repoRoot string) ([]StashEntry, error) {
+	cmd := exec.Command("git", "stash", "list", "--format=%gd%x1f%H%x1f%cr%x1f%gs")
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute git stash list: %w", err)
+	}
+
+	var stashes []StashEntry
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, "\x1f", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		stashes = append(stashes, StashEntry{
+			Ref:     fields[0],
+			Hash:    fields[1],
+			Age:     fields[2],
+			Message: fields[3],
+		})
+	}
+	return stashes, nil
+}
+
+// GetStashFiles returns the files changed by a stash, including the
+// untracked files it saved
+func GetStashFiles(stash StashEntry, repoRoot string) ([]FileInfo, error) {
+	// A stash commit's first parent is the commit it was made on
+	files, err := GetRevisionChangedFiles(RevisionRange{From: stash.Hash + "^1", To: stash.Hash}, repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := stashUntrackedFiles(stash, repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range untracked {
+		files = append(files, FileInfo{Path: path, ChangeStatus: "added"})
+	}
+	return files, nil
+}
+
+// stashUntrackedFiles lists the files of the untracked-files commit (third parent), if any
+func stashUntrackedFiles(stash StashEntry, repoRoot string) ([]string, error) {
+	if !hasUntrackedCommit(stash, repoRoot) {
+		return nil, nil
+	}
+	cmd := exec.Command("git", "ls-tree", "-r", "-z", "--name-only", stash.Hash+"^3")
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files of %s: %w", stash.Ref, err)
+	}
+	var paths []string
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// hasUntrackedCommit reports whether the stash was made with --include-untracked
+func hasUntrackedCommit(stash StashEntry, repoRoot string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", stash.Hash+"^3")
+	cmd.Dir = repoRoot
+	return cmd.Run() == nil
+}
+
+// GetStashDiffWithOptions returns the diff of a single file in a stash
+func GetStashDiffWithOptions(filePath string, repoRoot string, stash StashEntry, ignoreWhitespace bool) (string, error) {
+	diffText, err := GetRevisionDiffWithOptions(filePath, repoRoot, RevisionRange{From: stash.Hash + "^1", To: stash.Hash}, ignoreWhitespace)
+	if err != nil || diffText != "" || !hasUntrackedCommit(stash, repoRoot) {
+		return diffText, err
+	}
+
+	// Untracked files are only in the third parent, a root commit
+	args := []string{"-c", "core.quotepath=false", "show", "--format="}
+	if ignoreWhitespace {
+		args = append(args, "-w")
+	}
+	args = append(args, stash.Hash+"^3", "--", filePath)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to execute git show: %w", err)
+	}
+	return strings.TrimLeft(string(output), "\n"), nil
+}
+
+// StashPush stashes the changes selected by mode.
+// paths is only used with StashPaths.
+func StashPush(repoRoot string, mode StashMode, message string, paths ...string) error {
+	args := []string{"-c", "core.quotepath=false", "stash", "push"}
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+	switch mode {
+	case StashAll:
+		args = append(args, "--include-untracked")
+	case StashStaged:
+		args = append(args, "--staged")
+	case StashPaths:
+		if len(paths) == 0 {
+			return fmt.Errorf("no paths to stash")
+		}
+		args = append(args, "--include-untracked", "--")
+		args = append(args, paths...)
+	}
+	return runGit(repoRoot, args...)
+}
+
+// ApplyStash applies a stash, restoring its staged changes to the index
+func ApplyStash(repoRoot string, stash StashEntry) error {
+	return runGit(repoRoot, "stash", "apply", "--index", stash.Ref)
+}
+
+// PopStash applies a stash and drops it
+func PopStash(repoRoot string, stash StashEntry) error {
+	return runGit(repoRoot, "stash", "pop", "--index", stash.Ref)
+}
+
+// DropStash removes a stash
+func DropStash(repoRoot string, stash StashEntry) error {
+	return runGit(repoRoot, "stash", "drop", stash.Ref)
+}
+
+// BranchFromStash creates and checks out a branch at the commit the stash was
+// made on, applies the stash there and drops it
+func BranchFromStash(repoRoot string, branch string, stash StashEntry) error {
+	return runGit(repoRoot, "stash", "branch", branch, stash.Ref)
+}
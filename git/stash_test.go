@@ -0,0 +1,117 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestStash_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("a.txt", "a\n")
+	writeFile("b.txt", "b\n")
+	runGit("add", ".")
+	runGit("commit", "-m", "initial")
+
+	// Stash only the staged change
+	writeFile("a.txt", "a staged\n")
+	runGit("add", "a.txt")
+	writeFile("b.txt", "b unstaged\n")
+	if err := StashPush(tmpDir, StashStaged, "staged only"); err != nil {
+		t.Fatalf("StashPush(StashStaged) failed: %v", err)
+	}
+	if status := runGit("status", "--porcelain"); status != "M b.txt" {
+		t.Errorf("status after staged stash = %q, want %q", status, "M b.txt")
+	}
+
+	// Stash a single path, including a new file elsewhere that must stay
+	writeFile("new.txt", "new\n")
+	if err := StashPush(tmpDir, StashPaths, "", "new.txt"); err != nil {
+		t.Fatalf("StashPush(StashPaths) failed: %v", err)
+	}
+	if status := runGit("status", "--porcelain"); status != "M b.txt" {
+		t.Errorf("status after path stash = %q, want %q", status, "M b.txt")
+	}
+
+	stashes, err := ListStashes(tmpDir)
+	if err != nil {
+		t.Fatalf("ListStashes failed: %v", err)
+	}
+	if len(stashes) != 2 || stashes[1].Ref != "stash@{1}" || !strings.Contains(stashes[1].Message, "staged only") {
+		t.Fatalf("unexpected stashes: %+v", stashes)
+	}
+
+	// The untracked file is listed and has a diff
+	files, err := GetStashFiles(stashes[0], tmpDir)
+	if err != nil {
+		t.Fatalf("GetStashFiles failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "new.txt" || files[0].ChangeStatus != "added" {
+		t.Errorf("unexpected stash files: %+v", files)
+	}
+	diffText, err := GetStashDiffWithOptions("new.txt", tmpDir, stashes[0], false)
+	if err != nil || !strings.Contains(diffText, "+new") {
+		t.Errorf("unexpected untracked diff (err: %v):\n%s", err, diffText)
+	}
+
+	files, err = GetStashFiles(stashes[1], tmpDir)
+	if err != nil || len(files) != 1 || files[0].Path != "a.txt" {
+		t.Errorf("unexpected stash files: %+v (err: %v)", files, err)
+	}
+
+	// Pop restores the file; drop removes the other stash
+	if err := PopStash(tmpDir, stashes[0]); err != nil {
+		t.Fatalf("PopStash failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "new.txt")); err != nil {
+		t.Errorf("expected new.txt to be restored: %v", err)
+	}
+	stashes, _ = ListStashes(tmpDir)
+	if err := DropStash(tmpDir, stashes[0]); err != nil {
+		t.Fatalf("DropStash failed: %v", err)
+	}
+	if stashes, _ = ListStashes(tmpDir); len(stashes) != 0 {
+		t.Errorf("expected no stashes, got %+v", stashes)
+	}
+}
+
+func TestBranchFromStash(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+
+	filePath := filepath.Join(tmpDir, "file.txt")
+	if err := os.WriteFile(filePath, []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit("add", ".")
+	runGit("commit", "-m", "initial")
+	if err := os.WriteFile(filePath, []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := StashPush(tmpDir, StashAll, "wip"); err != nil {
+		t.Fatalf("StashPush failed: %v", err)
+	}
+
+	stashes, err := ListStashes(tmpDir)
+	if err != nil || len(stashes) != 1 {
+		t.Fatalf("unexpected stashes: %+v (err: %v)", stashes, err)
+	}
+	if err := BranchFromStash(tmpDir, "from-stash", stashes[0]); err != nil {
+		t.Fatalf("BranchFromStash failed: %v", err)
+	}
+	if branch := runGit("branch", "--show-current"); branch != "from-stash" {
+		t.Errorf("current branch = %q, want from-stash", branch)
+	}
+	if content, _ := os.ReadFile(filePath); string(content) != "two\n" {
+		t.Errorf("file content = %q, want %q", content, "two\n")
+	}
+}
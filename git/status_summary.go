@@ -0,0 +1,132 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// HeadStatus describes what is checked out and how it compares to its upstream
+type HeadStatus struct {
+	Branch       string // empty when HEAD is detached
+	Hash         string // empty before the first commit
+	Subject      string // subject of the HEAD commit
+	Upstream     string // e.g. "origin/main", empty if not tracking
+	UpstreamGone bool   // the upstream is configured but its ref no longer exists
+	Ahead        int
+	Behind       int
+}
+
+// GetHeadStatus returns the branch, upstream and last commit of HEAD,
+// computed from local refs only (nothing is fetched)
+func GetHeadStatus(repoRoot string) (HeadStatus, error) {
+	// symbolic-ref fails quietly when HEAD is detached
+	cmd := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD")
+	cmd.Dir = repoRoot
+	output, _ := cmd.Output()
+	branch := strings.TrimSpace(string(output))
+
+	if branch == "" {
+		cmd := exec.Command("git", "log", "-1", "--format=%H%x1f%s")
+		cmd.Dir = repoRoot
+		output, err := cmd.Output()
+		if err != nil {
+			return HeadStatus{}, fmt.Errorf("failed to read HEAD: %w", err)
+		}
+		hash, subject, _ := strings.Cut(strings.TrimSpace(string(output)), "\x1f")
+		return HeadStatus{Hash: hash, Subject: subject}, nil
+	}
+
+	// The branch has no ref yet before the first commit
+	branches, err := readBranches(repoRoot, "refs/heads/"+branch)
+	if err != nil {
+		return HeadStatus{}, err
+	}
+	status := HeadStatus{Branch: branch}
+	for _, b := range branches {
+		if b.Name == branch {
+			status.Hash = b.Hash
+			status.Subject = b.Subject
+			status.Upstream = b.Upstream
+			status.UpstreamGone = b.UpstreamGone
+			status.Ahead = b.Ahead
+			status.Behind = b.Behind
+		}
+	}
+	return status, nil
+}
+
+// ChangeCount is the number of changed files and lines in one area
+type ChangeCount struct {
+	Files   int
+	Added   int
+	Deleted int
+}
+
+// WorkingTreeSummary counts the staged, unstaged and untracked changes
+type WorkingTreeSummary struct {
+	Staged    ChangeCount
+	Unstaged  ChangeCount
+	Untracked int // number of untracked files
+}
+
+// GetWorkingTreeSummary counts the changed files and lines of the index and
+// the working tree, and the untracked files
+func GetWorkingTreeSummary(repoRoot string) (WorkingTreeSummary, error) {
+	var summary WorkingTreeSummary
+	var err error
+	if summary.Staged, err = numstat(repoRoot, "--cached"); err != nil {
+		return summary, err
+	}
+	if summary.Unstaged, err = numstat(repoRoot); err != nil {
+		return summary, err
+	}
+
+	cmd := exec.Command("git", "ls-files", "--others", "--exclude-standard", "-z")
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return summary, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path != "" {
+			summary.Untracked++
+		}
+	}
+	return summary, nil
+}
+
+// numstat sums `git diff --numstat` with the given options
+func numstat(repoRoot string, args ...string) (ChangeCount, error) {
+	cmd := exec.Command("git", append([]string{"diff", "--numstat", "-z"}, args...)...)
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return ChangeCount{}, fmt.Errorf("failed to execute git diff --numstat: %w", err)
+	}
+	return parseNumstat(string(output)), nil
+}
+
+// parseNumstat parses `git diff --numstat -z`. Binary files count as a file
+// without lines; renames are followed by the old and new path.
+func parseNumstat(output string) ChangeCount {
+	var count ChangeCount
+	records := strings.Split(output, "\x00")
+	for i := 0; i < len(records); i++ {
+		fields := strings.SplitN(records[i], "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		if fields[2] == "" {
+			// Rename: the two paths follow as separate records
+			i += 2
+		}
+		count.Files++
+		added, _ := strconv.Atoi(fields[0])
+		deleted, _ := strconv.Atoi(fields[1])
+		count.Added += added
+		count.Deleted += deleted
+	}
+	return count
+}
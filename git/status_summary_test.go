@@ -0,0 +1,86 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestParseNumstat(t *testing.T) {
+	output := "3\t1\ta.txt\x00-\t-\timage.png\x000\t0\t\x00old.txt\x00new.txt\x00"
+	want := ChangeCount{Files: 3, Added: 3, Deleted: 1}
+	if got := parseNumstat(output); got != want {
+		t.Errorf("parseNumstat() = %+v, want %+v", got, want)
+	}
+}
+
+func TestStatusSummary_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	status, err := GetHeadStatus(tmpDir)
+	if err != nil {
+		t.Fatalf("GetHeadStatus failed: %v", err)
+	}
+	if status.Branch != "main" || status.Hash != "" {
+		t.Errorf("status before the first commit = %+v", status)
+	}
+
+	writeFile("a.txt", "1\n2\n3\n")
+	runGit("add", "a.txt")
+	runGit("commit", "-m", "first")
+	runGit("branch", "upstream")
+	runGit("branch", "--set-upstream-to=upstream")
+	writeFile("a.txt", "1\n2\nthree\n")
+	runGit("commit", "-am", "second")
+
+	status, err = GetHeadStatus(tmpDir)
+	if err != nil {
+		t.Fatalf("GetHeadStatus failed: %v", err)
+	}
+	if status.Branch != "main" || status.Upstream != "upstream" || status.Ahead != 1 || status.Behind != 0 || status.Subject != "second" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	writeFile("b.txt", "b\n")
+	runGit("add", "b.txt")
+	writeFile("a.txt", "1\n")
+	writeFile("new.txt", "x\ny")
+	summary, err := GetWorkingTreeSummary(tmpDir)
+	if err != nil {
+		t.Fatalf("GetWorkingTreeSummary failed: %v", err)
+	}
+	want := WorkingTreeSummary{
+		Staged:    ChangeCount{Files: 1, Added: 1},
+		Unstaged:  ChangeCount{Files: 1, Deleted: 2},
+		Untracked: 1,
+	}
+	if summary != want {
+		t.Errorf("GetWorkingTreeSummary() = %+v, want %+v", summary, want)
+	}
+
+	runGit("branch", "-D", "upstream")
+	status, err = GetHeadStatus(tmpDir)
+	if err != nil {
+		t.Fatalf("GetHeadStatus failed: %v", err)
+	}
+	if status.Upstream != "upstream" || !status.UpstreamGone {
+		t.Errorf("status with a deleted upstream = %+v", status)
+	}
+
+	runGit("checkout", "--detach")
+	status, err = GetHeadStatus(tmpDir)
+	if err != nil {
+		t.Fatalf("GetHeadStatus failed: %v", err)
+	}
+	if status.Branch != "" || status.Hash == "" || status.Subject != "second" {
+		t.Errorf("status of a detached HEAD = %+v", status)
+	}
+}
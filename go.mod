@@ -2,13 +2,15 @@ module github.com/sukechannnn/giff
 
 go 1.23.5
 
-require github.com/go-git/go-git/v5 v5.13.1
+require (
+	github.com/alecthomas/chroma/v2 v2.23.1
+	github.com/gdamore/tcell/v2 v2.7.1
+	github.com/go-git/go-git/v5 v5.13.1
+)
 
 require (
-	github.com/alecthomas/chroma/v2 v2.23.1 // indirect
 	github.com/dlclark/regexp2 v1.11.5 // indirect
 	github.com/gdamore/encoding v1.0.0 // indirect
-	github.com/gdamore/tcell/v2 v2.7.1 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-runewidth v0.0.15 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
@@ -30,7 +32,7 @@ require (
 	github.com/kevinburke/ssh_config v1.2.0 // indirect
 	github.com/pjbgf/sha1cd v0.3.0 // indirect
 	github.com/rivo/tview v0.0.0-20241227133733-17b7edb88c57
-	github.com/sergi/go-diff v1.3.2-0.20230802210424-5b0b94c5c0d3 // indirect
+	github.com/sergi/go-diff v1.3.2-0.20230802210424-5b0b94c5c0d3
 	github.com/skeema/knownhosts v1.3.0 // indirect
 	github.com/xanzy/ssh-agent v0.3.3 // indirect
 	golang.org/x/crypto v0.31.0 // indirect
@@ -28,9 +28,15 @@ type Application struct {
 func main() {
 	var autoRefresh bool
 	var showVersion bool
+	var baseBranch string
 	flag.BoolVar(&autoRefresh, "watch", false, "Watch for file changes and auto-refresh")
 	flag.BoolVar(&autoRefresh, "w", false, "Watch for file changes and auto-refresh (shorthand)")
 	flag.BoolVar(&showVersion, "v", false, "Show version")
+	flag.StringVar(&baseBranch, "base", "", "Review all changes since the merge-base with the given branch")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: giff [options] [<rev> | <rev1>..<rev2> | <rev1>...<rev2>]\n")
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
 	if showVersion {
@@ -43,6 +49,10 @@ func main() {
 		return
 	}
 
+	if baseBranch != "" && flag.NArg() > 0 {
+		log.Fatalf("--base cannot be combined with a revision (%s)", flag.Arg(0))
+	}
+
 	// Create shell snapshot for terminal command aliases
 	util.CreateShellSnapshot()
 	defer util.CleanupShellSnapshot()
@@ -99,6 +109,36 @@ func main() {
 		return event // Process other events normally
 	})
 
+	// Base mode: show every change since the merge-base with the base branch
+	if baseBranch != "" {
+		baseView, err := ui.BaseView(giffApp.App, repoPath, baseBranch)
+		if err != nil {
+			log.Fatalf("Failed to compare with %s: %v", baseBranch, err)
+		}
+		giffApp.App.SetRoot(baseView, true)
+		if err := giffApp.App.Run(); err != nil {
+			log.Fatalf("Error running application: %v", err)
+		}
+		return
+	}
+
+	// Revision range mode: show the comparison read-only
+	if flag.NArg() > 0 {
+		revisionRange := git.ParseRevisionRange(flag.Arg(0))
+		if err := git.ValidateRevisionRange(revisionRange, repoPath); err != nil {
+			log.Fatalf("Invalid revision: %v", err)
+		}
+		revisionView, err := ui.RevisionView(giffApp.App, repoPath, revisionRange)
+		if err != nil {
+			log.Fatalf("Failed to get changed files: %v", err)
+		}
+		giffApp.App.SetRoot(revisionView, true)
+		if err := giffApp.App.Run(); err != nil {
+			log.Fatalf("Error running application: %v", err)
+		}
+		return
+	}
+
 	// Get files with changes
 	stagedFiles, modifiedFiles, untrackedFiles, err := git.GetChangedFiles(repoPath)
 	if err != nil {
@@ -0,0 +1,362 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+	"github.com/sukechannnn/giff/util"
+)
+
+var blameViewKeyMessage = "j/k:move  gg/G:top/bottom  Enter:show commit  p:blame parent  Esc:back  q:quit"
+
+// maxBlameAuthorWidth limits the author column of the blame view
+const maxBlameAuthorWidth = 20
+
+// blameTarget is a file at a revision ("" = working tree) with the line to show
+type blameTarget struct {
+	rev  string
+	path string
+	line int // 1-based
+}
+
+// BlameView shows git blame of a file and steps back through its history
+type BlameView struct {
+	app         *tview.Application
+	blameView   *tview.TextView
+	statusView  *tview.TextView
+	flex        *tview.Flex
+	repoRoot    string
+	target      blameTarget
+	history     []blameTarget // previous targets, for Esc
+	lines       []git.BlameLine
+	currentLine int
+	gPressed    bool
+	lastGTime   time.Time
+	onExit      func()
+}
+
+// NewBlameView creates a blame view of filePath at rev ("" = working tree), with the cursor on line
+func NewBlameView(app *tview.Application, repoRoot, rev, filePath string, line int, onExit func()) (*BlameView, error) {
+	blameView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false)
+	blameView.SetBorder(true)
+	blameView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	statusView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false).
+		SetText(blameViewKeyMessage)
+	statusView.SetBorder(true)
+	statusView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(blameView, 0, 1, true).
+		AddItem(statusView, 3, 0, false)
+
+	bv := &BlameView{
+		app:        app,
+		blameView:  blameView,
+		statusView: statusView,
+		flex:       flex,
+		repoRoot:   repoRoot,
+		onExit:     onExit,
+	}
+
+	if err := bv.load(blameTarget{rev: rev, path: filePath, line: line}); err != nil {
+		return nil, err
+	}
+	bv.setupKeyBindings()
+
+	return bv, nil
+}
+
+// GetView returns the main flex view
+func (bv *BlameView) GetView() tview.Primitive {
+	return bv.flex
+}
+
+// load blames target and renders it with the cursor on target.line
+func (bv *BlameView) load(target blameTarget) error {
+	lines, err := git.GetBlame(bv.repoRoot, target.rev, target.path)
+	if err != nil {
+		return err
+	}
+	bv.target = target
+	bv.lines = lines
+
+	title := "Blame: " + target.path + " (working tree)"
+	if strings.HasSuffix(target.rev, "^") {
+		title = "Blame: " + target.path + " @ " + shortHash(strings.TrimSuffix(target.rev, "^")) + "^"
+	} else if target.rev != "" {
+		title = "Blame: " + target.path + " @ " + target.rev
+	}
+	bv.blameView.SetTitle(tview.Escape(title))
+
+	bv.render()
+	bv.moveTo(target.line - 1)
+	return nil
+}
+
+// render writes every blame line as a region so the current one can be highlighted
+func (bv *BlameView) render() {
+	authorWidth := 0
+	for _, line := range bv.lines {
+		authorWidth = max(authorWidth, len([]rune(blameAuthor(line))))
+	}
+	authorWidth = min(authorWidth, maxBlameAuthorWidth)
+	lineNumberWidth := len(strconv.Itoa(len(bv.lines)))
+
+	var sb strings.Builder
+	for i, line := range bv.lines {
+		date := ""
+		if !line.IsUncommitted() {
+			date = line.Time.Format("2006-01-02")
+		}
+		author := []rune(blameAuthor(line))
+		if len(author) > authorWidth {
+			author = author[:authorWidth]
+		}
+		fmt.Fprintf(&sb, "[\"%d\"][yellow]%s[-] [aqua]%-*s[-] %-10s [gray]%*d[-] │ %s[\"\"]\n",
+			i, shortHash(line.Hash), authorWidth, tview.Escape(string(author)), date,
+			lineNumberWidth, line.Line, tview.Escape(line.Content))
+	}
+	bv.blameView.SetText(sb.String())
+}
+
+// blameAuthor returns the author shown for a blame line
+func blameAuthor(line git.BlameLine) string {
+	if line.IsUncommitted() {
+		return "Not Committed Yet"
+	}
+	return line.Author
+}
+
+// moveTo moves the cursor to the given line index and keeps it visible
+func (bv *BlameView) moveTo(index int) {
+	if len(bv.lines) == 0 {
+		return
+	}
+	index = max(0, min(index, len(bv.lines)-1))
+	bv.currentLine = index
+	bv.blameView.Highlight(strconv.Itoa(index))
+	bv.blameView.ScrollToHighlight()
+}
+
+// selected returns the blame line under the cursor
+func (bv *BlameView) selected() (git.BlameLine, bool) {
+	if bv.currentLine >= len(bv.lines) {
+		return git.BlameLine{}, false
+	}
+	return bv.lines[bv.currentLine], true
+}
+
+// setStatus shows a message in the status bar and restores the key help later
+func (bv *BlameView) setStatus(message, color string) {
+	bv.statusView.SetText(fmt.Sprintf("[%s]%s[-]", color, tview.Escape(message)))
+	go func() {
+		time.Sleep(5 * time.Second)
+		bv.app.QueueUpdateDraw(func() {
+			bv.statusView.SetText(blameViewKeyMessage)
+		})
+	}()
+}
+
+// showCommit opens the commit of the selected line in the commit detail view
+func (bv *BlameView) showCommit() {
+	line, ok := bv.selected()
+	if !ok {
+		return
+	}
+	if line.IsUncommitted() {
+		bv.setStatus("This line is not committed yet", "yellow")
+		return
+	}
+
+	files := getCommitFiles(bv.repoRoot, line.Hash)
+	layout := newCommitDetailLayout(bv.app, bv.repoRoot, line.Hash, files, bv.flex, bv.backToBlame)
+	layout.View.SetTitle(" " + shortHash(line.Hash) + " " + tview.Escape(line.Summary) + " ")
+
+	bv.flex.Clear()
+	bv.flex.AddItem(layout.View, 0, 1, true)
+	bv.app.SetFocus(layout.FileListView)
+}
+
+// backToBlame returns to the blame from the commit detail view
+func (bv *BlameView) backToBlame() {
+	bv.flex.Clear()
+	bv.flex.AddItem(bv.blameView, 0, 1, true)
+	bv.flex.AddItem(bv.statusView, 3, 0, false)
+	bv.app.SetFocus(bv.blameView)
+}
+
+// blameParent re-blames the file at the parent of the selected line's commit,
+// to see what the line looked like before that commit
+func (bv *BlameView) blameParent() {
+	line, ok := bv.selected()
+	if !ok {
+		return
+	}
+	if line.IsUncommitted() {
+		bv.setStatus("This line is not committed yet", "yellow")
+		return
+	}
+	if line.Boundary {
+		bv.setStatus(shortHash(line.Hash)+" has no parent", "yellow")
+		return
+	}
+
+	previous := bv.target
+	previous.line = bv.currentLine + 1
+	target := blameTarget{rev: line.Hash + "^", path: line.OrigPath, line: line.OrigLine}
+	if err := bv.load(target); err != nil {
+		bv.setStatus(err.Error(), "tomato")
+		return
+	}
+	bv.history = append(bv.history, previous)
+}
+
+// back returns to the previous blame, or leaves the view
+func (bv *BlameView) back() {
+	if len(bv.history) == 0 {
+		if bv.onExit != nil {
+			bv.onExit()
+		}
+		return
+	}
+	previous := bv.history[len(bv.history)-1]
+	bv.history = bv.history[:len(bv.history)-1]
+	if err := bv.load(previous); err != nil {
+		bv.setStatus(err.Error(), "tomato")
+	}
+}
+
+// setupKeyBindings configures keyboard navigation for the blame view
+func (bv *BlameView) setupKeyBindings() {
+	bv.blameView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			bv.back()
+			return nil
+		case tcell.KeyEnter:
+			bv.showCommit()
+			return nil
+		case tcell.KeyDown:
+			bv.moveTo(bv.currentLine + 1)
+			return nil
+		case tcell.KeyUp:
+			bv.moveTo(bv.currentLine - 1)
+			return nil
+		case tcell.KeyCtrlD:
+			_, _, _, height := bv.blameView.GetInnerRect()
+			bv.moveTo(bv.currentLine + max(height/2, 1))
+			return nil
+		case tcell.KeyCtrlU:
+			_, _, _, height := bv.blameView.GetInnerRect()
+			bv.moveTo(bv.currentLine - max(height/2, 1))
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'j':
+			bv.moveTo(bv.currentLine + 1)
+			return nil
+		case 'k':
+			bv.moveTo(bv.currentLine - 1)
+			return nil
+		case 'g':
+			now := time.Now()
+			if bv.gPressed && now.Sub(bv.lastGTime) < 500*time.Millisecond {
+				bv.moveTo(0)
+				bv.gPressed = false
+			} else {
+				bv.gPressed = true
+				bv.lastGTime = now
+			}
+			return nil
+		case 'G':
+			bv.moveTo(len(bv.lines) - 1)
+			return nil
+		case 'p':
+			bv.blameParent()
+			return nil
+		case 'q':
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				os.Exit(0)
+			}()
+			bv.app.Stop()
+			return nil
+		}
+
+		return event
+	})
+}
+
+// openBlameAtCursor opens the blame of the current file at the line under the cursor
+func openBlameAtCursor(ctx *DiffViewContext) {
+	if *ctx.currentFile == "" {
+		return
+	}
+	rev, line, ok := cursorFileLine(ctx)
+	if !ok {
+		line = 1
+	}
+
+	var focusView tview.Primitive = ctx.diffView
+	if *ctx.isSplitView {
+		focusView = ctx.splitViewFlex
+	}
+	blameView, err := NewBlameView(ctx.app, ctx.repoRoot, rev, *ctx.currentFile, line, func() {
+		ctx.app.SetRoot(ctx.mainView, true)
+		ctx.app.SetFocus(focusView)
+	})
+	if err != nil {
+		ctx.updateGlobalStatus(err.Error(), "tomato")
+		return
+	}
+	ctx.app.SetRoot(blameView.GetView(), true)
+}
+
+// cursorFileLine returns the file line under the diff view cursor and the
+// revision to blame it at (see blameLineAt)
+func cursorFileLine(ctx *DiffViewContext) (rev string, line int, ok bool) {
+	var lineIdx int
+	if *ctx.isSplitView {
+		rows := splitViewRows(*ctx.currentDiffText)
+		if *ctx.cursorY < 0 || *ctx.cursorY >= len(rows) {
+			return "", 0, false
+		}
+		row := rows[*ctx.cursorY]
+		lineIdx = row[len(row)-1] // prefer the new side of a paired row
+	} else {
+		displayMapping := MapUnifiedDisplayToOriginalIdx(*ctx.currentDiffText, ctx.foldState, *ctx.currentFile, ctx.repoRoot)
+		idx, found := displayMapping[*ctx.cursorY]
+		if !found {
+			return "", 0, false
+		}
+		lineIdx = idx
+	}
+	return blameLineAt(*ctx.currentDiffText, lineIdx)
+}
+
+// blameLineAt returns the file line of a diff line (indexed like
+// createLineNumberMapping) and the revision to blame it at: the working tree
+// for new and context lines, HEAD for deleted lines
+func blameLineAt(diffText string, lineIdx int) (rev string, line int, ok bool) {
+	oldLineMap, newLineMap := createLineNumberMapping(diffText)
+	if newLine, found := newLineMap[lineIdx]; found {
+		return "", newLine, true
+	}
+	if oldLine, found := oldLineMap[lineIdx]; found {
+		return "HEAD", oldLine, true
+	}
+	return "", 0, false
+}
@@ -0,0 +1,44 @@
+package ui
+
+import "testing"
+
+func TestBlameLineAt(t *testing.T) {
+	diffText := `diff --git a/file.txt b/file.txt
+index 1234567..abcdefg 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1,4 +1,4 @@
+ line1
+-old2
++new2
+ line3
+@@ -10,2 +10,3 @@
+ line10
++added
+ line11`
+
+	tests := []struct {
+		lineIdx int
+		rev     string
+		line    int
+	}{
+		{0, "", 1},     // line1
+		{1, "HEAD", 2}, // -old2
+		{2, "", 2},     // +new2
+		{3, "", 3},     // line3
+		{4, "", 10},
+		{5, "", 11}, // +added
+		{6, "", 12},
+	}
+	for _, tt := range tests {
+		rev, line, ok := blameLineAt(diffText, tt.lineIdx)
+		if !ok || rev != tt.rev || line != tt.line {
+			t.Errorf("blameLineAt(%d) = %q, %d, %v; want %q, %d",
+				tt.lineIdx, rev, line, ok, tt.rev, tt.line)
+		}
+	}
+
+	if _, _, ok := blameLineAt(diffText, 7); ok {
+		t.Error("expected no line past the end of the diff")
+	}
+}
@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+	"github.com/sukechannnn/giff/util"
+)
+
+var branchViewKeyMessage = "j/k:move  Enter:diff vs HEAD  c:checkout  n:new from HEAD  r:rename  d:delete  Esc:back  q:quit"
+
+// BranchView lists the local branches and checks out, creates, renames or deletes them
+type BranchView struct {
+	app         *tview.Application
+	listView    *tview.TextView
+	statusView  *tview.TextView
+	flex        *tview.Flex
+	repoRoot    string
+	branches    []git.Branch
+	currentLine int
+	changed     bool // HEAD or the working tree changed (checkout)
+	onExit      func(changed bool)
+}
+
+// NewBranchView creates a view of the local branches
+func NewBranchView(app *tview.Application, repoRoot string, onExit func(changed bool)) *BranchView {
+	listView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	listView.SetBorder(true).SetTitle("Branches")
+	listView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	statusView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false).
+		SetText(branchViewKeyMessage)
+	statusView.SetBorder(true)
+	statusView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(listView, 0, 1, true).
+		AddItem(statusView, 3, 0, false)
+
+	bv := &BranchView{
+		app:        app,
+		listView:   listView,
+		statusView: statusView,
+		flex:       flex,
+		repoRoot:   repoRoot,
+		onExit:     onExit,
+	}
+
+	bv.setupKeyBindings()
+	bv.reload()
+
+	// Start on the current branch
+	for i, branch := range bv.branches {
+		if branch.Current {
+			bv.currentLine = i
+			bv.updateList()
+			break
+		}
+	}
+
+	return bv
+}
+
+// GetView returns the main flex view
+func (bv *BranchView) GetView() tview.Primitive {
+	return bv.flex
+}
+
+// reload re-reads the branches and redraws the list
+func (bv *BranchView) reload() {
+	branches, err := git.ListBranches(bv.repoRoot)
+	if err != nil {
+		bv.listView.SetText("[red]Failed to load branches: " + tview.Escape(err.Error()))
+		return
+	}
+	bv.branches = branches
+	if bv.currentLine >= len(bv.branches) {
+		bv.currentLine = len(bv.branches) - 1
+	}
+	if bv.currentLine < 0 {
+		bv.currentLine = 0
+	}
+	bv.updateList()
+}
+
+// formatTracking returns the upstream and ahead/behind counts of a branch
+func formatTracking(branch git.Branch) string {
+	if branch.Upstream == "" {
+		return ""
+	}
+	if branch.UpstreamGone {
+		return "[" + branch.Upstream + ": gone]"
+	}
+	var counts []string
+	if branch.Ahead > 0 {
+		counts = append(counts, fmt.Sprintf("↑%d", branch.Ahead))
+	}
+	if branch.Behind > 0 {
+		counts = append(counts, fmt.Sprintf("↓%d", branch.Behind))
+	}
+	if len(counts) == 0 {
+		return "[" + branch.Upstream + "]"
+	}
+	return "[" + branch.Upstream + " " + strings.Join(counts, " ") + "]"
+}
+
+// updateList renders the branches with the current line highlighted
+func (bv *BranchView) updateList() {
+	if len(bv.branches) == 0 {
+		bv.listView.SetText("No branches")
+		return
+	}
+
+	var sb strings.Builder
+	for i, branch := range bv.branches {
+		marker := "  "
+		if branch.Current {
+			marker = "* "
+		}
+		tracking := formatTracking(branch)
+		if tracking != "" {
+			tracking = " " + tview.Escape(tracking)
+		}
+		if i == bv.currentLine {
+			sb.WriteString(fmt.Sprintf("[white:blue]%s%s%s  %s %s[-:-]\n",
+				marker, tview.Escape(branch.Name), tracking, shortHash(branch.Hash), tview.Escape(branch.Subject)))
+			continue
+		}
+		name := tview.Escape(branch.Name)
+		if branch.Current {
+			name = "[green]" + name + "[-]"
+		}
+		sb.WriteString(fmt.Sprintf("%s%s[aqua]%s[-]  [yellow]%s[-] %s\n",
+			marker, name, tracking, shortHash(branch.Hash), tview.Escape(branch.Subject)))
+	}
+	bv.listView.SetText(sb.String())
+}
+
+// selected returns the branch under the cursor
+func (bv *BranchView) selected() (git.Branch, bool) {
+	if bv.currentLine >= len(bv.branches) {
+		return git.Branch{}, false
+	}
+	return bv.branches[bv.currentLine], true
+}
+
+// setStatus shows a message in the status bar
+func (bv *BranchView) setStatus(message, color string) {
+	bv.statusView.SetText(fmt.Sprintf("[%s]%s[-]", color, tview.Escape(message)))
+}
+
+// showBranchDiff shows the difference between HEAD and the selected branch
+func (bv *BranchView) showBranchDiff() {
+	branch, ok := bv.selected()
+	if !ok {
+		return
+	}
+
+	revisionRange := git.RevisionRange{From: "HEAD", To: branch.Name}
+	layout, count, err := newRevisionDiffLayout(bv.app, bv.repoRoot, revisionRange, readOnlyDiffKeyMessage, bv.flex, bv.backToList)
+	if err != nil {
+		bv.setStatus(err.Error(), "tomato")
+		return
+	}
+	if count == 0 {
+		bv.setStatus("No differences between HEAD and "+branch.Name, "yellow")
+		return
+	}
+
+	bv.flex.Clear()
+	bv.flex.AddItem(layout.View, 0, 1, true)
+	bv.app.SetFocus(layout.FileListView)
+}
+
+// backToList returns to the branch list from the branch diff
+func (bv *BranchView) backToList() {
+	bv.flex.Clear()
+	bv.flex.AddItem(bv.listView, 0, 1, true)
+	bv.flex.AddItem(bv.statusView, 3, 0, false)
+	bv.app.SetFocus(bv.listView)
+}
+
+// checkout switches to the selected branch
+func (bv *BranchView) checkout() {
+	branch, ok := bv.selected()
+	if !ok || branch.Current {
+		return
+	}
+	if err := git.CheckoutBranch(bv.repoRoot, branch.Name); err != nil {
+		bv.setStatus(err.Error(), "tomato")
+		return
+	}
+	bv.changed = true
+	bv.reload()
+	bv.setStatus("Switched to "+branch.Name, "forestgreen")
+}
+
+// promptCreate asks for a name and creates a branch at HEAD
+func (bv *BranchView) promptCreate() {
+	showInputPrompt(bv.app, bv.flex, "New branch from HEAD: ", "", bv.listView, func(name string) {
+		if err := git.CreateBranch(bv.repoRoot, name, "HEAD"); err != nil {
+			bv.setStatus(err.Error(), "tomato")
+			return
+		}
+		bv.reload()
+		bv.setStatus("Created branch "+name, "forestgreen")
+	})
+}
+
+// promptRename asks for a new name for the selected branch
+func (bv *BranchView) promptRename() {
+	branch, ok := bv.selected()
+	if !ok {
+		return
+	}
+	showInputPrompt(bv.app, bv.flex, "Rename "+branch.Name+" to: ", branch.Name, bv.listView, func(name string) {
+		if name == branch.Name {
+			return
+		}
+		if err := git.RenameBranch(bv.repoRoot, branch.Name, name); err != nil {
+			bv.setStatus(err.Error(), "tomato")
+			return
+		}
+		bv.reload()
+		bv.setStatus("Renamed "+branch.Name+" to "+name, "forestgreen")
+	})
+}
+
+// confirmDelete asks for confirmation before deleting the selected branch
+func (bv *BranchView) confirmDelete() {
+	branch, ok := bv.selected()
+	if !ok {
+		return
+	}
+	if branch.Current {
+		bv.setStatus("Cannot delete the checked out branch", "tomato")
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText("Delete branch " + tview.Escape(branch.Name) + "?").
+		AddButtons([]string{"Delete", "Force delete", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			bv.app.SetRoot(bv.flex, true)
+			bv.app.SetFocus(bv.listView)
+			if buttonLabel != "Delete" && buttonLabel != "Force delete" {
+				return
+			}
+			if err := git.DeleteBranch(bv.repoRoot, branch.Name, buttonLabel == "Force delete"); err != nil {
+				bv.setStatus(err.Error(), "tomato")
+				return
+			}
+			bv.reload()
+			bv.setStatus("Deleted branch "+branch.Name+" (was "+shortHash(branch.Hash)+")", "forestgreen")
+		})
+
+	pages := tview.NewPages().
+		AddPage("main", bv.flex, true, true).
+		AddPage("modal", modal, true, true)
+	bv.app.SetRoot(pages, true)
+}
+
+// setupKeyBindings configures keyboard navigation for the branch list
+func (bv *BranchView) setupKeyBindings() {
+	bv.listView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			if bv.onExit != nil {
+				bv.onExit(bv.changed)
+			}
+			return nil
+		case tcell.KeyEnter:
+			bv.showBranchDiff()
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'j':
+			if bv.currentLine < len(bv.branches)-1 {
+				bv.currentLine++
+				bv.updateList()
+			}
+			return nil
+		case 'k':
+			if bv.currentLine > 0 {
+				bv.currentLine--
+				bv.updateList()
+			}
+			return nil
+		case 'c':
+			bv.checkout()
+			return nil
+		case 'n':
+			bv.promptCreate()
+			return nil
+		case 'r':
+			bv.promptRename()
+			return nil
+		case 'd':
+			bv.confirmDelete()
+			return nil
+		case 'q':
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				os.Exit(0)
+			}()
+			bv.app.Stop()
+			return nil
+		}
+
+		return event
+	})
+}
@@ -2,7 +2,6 @@ package commands
 
 import (
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -47,19 +46,15 @@ func CommandA(params CommandAParams) (*CommandAResult, error) {
 	// Build file path
 	filePath := filepath.Join(params.RepoRoot, params.CurrentFile)
 
-	// Save current file content
-	originalContent, err := os.ReadFile(filePath)
-	if err != nil {
+	// Make sure the working tree file is readable (it is only read, never written)
+	if _, err := os.Lstat(filePath); err != nil {
 		if params.UpdateGlobalStatus != nil {
 			params.UpdateGlobalStatus("Failed to read file", "tomato")
 		}
 		return nil, err
 	}
 
-	// Get file content with only selected changes applied
 	mapping := MapDisplayToOriginalIdx(params.CurrentDiffText)
-	start := mapping[params.SelectStart]
-	end := mapping[params.SelectEnd]
 
 	// Check if the selection contains actual changes
 	// Create display lines (excluding headers)
@@ -99,47 +94,44 @@ func CommandA(params CommandAParams) (*CommandAResult, error) {
 		return result, nil
 	}
 
-	modifiedContent, _, err := git.ApplySelectedChangesToFile(params.CurrentFile, params.RepoRoot, params.CurrentDiffText, start, end)
-	if err != nil {
-		if params.UpdateGlobalStatus != nil {
-			params.UpdateGlobalStatus("Failed to process changes", "tomato")
+	// An untracked file is registered with intent-to-add only now that the
+	// selection is known to stage something, so it is staged on top of an
+	// empty index entry
+	if params.CurrentStatus == "untracked" {
+		diffText, err := prepareUntrackedForStaging(params.CurrentFile, params.RepoRoot)
+		if err != nil {
+			if params.UpdateGlobalStatus != nil {
+				params.UpdateGlobalStatus("Failed to add file to index", "tomato")
+			}
+			return nil, err
+		}
+		// The synthesized diff has the same layout as git diff of the
+		// intent-to-add entry, so the selection carries over
+		params.CurrentDiffText = diffText
+		mapping = MapDisplayToOriginalIdx(diffText)
+		if params.SelectEnd >= len(mapping) {
+			params.SelectEnd = len(mapping) - 1
 		}
-		return nil, err
 	}
 
-	// Temporarily overwrite file with only selected changes
-	err = os.WriteFile(filePath, []byte(modifiedContent), 0644)
+	// Get file content with only selected changes applied
+	start := mapping[params.SelectStart]
+	end := mapping[params.SelectEnd]
+
+	modifiedContent, _, err := git.ApplySelectedChangesToFile(params.CurrentFile, params.RepoRoot, params.CurrentDiffText, start, end)
 	if err != nil {
 		if params.UpdateGlobalStatus != nil {
-			params.UpdateGlobalStatus("Failed to write file", "tomato")
+			params.UpdateGlobalStatus("Failed to process changes", "tomato")
 		}
 		return nil, err
 	}
 
-	// Run git add
-	// -c core.quotepath=false prevents escaping of multibyte filenames
-	cmd := exec.Command("git", "-c", "core.quotepath=false", "add", params.CurrentFile)
-	cmd.Dir = params.RepoRoot
-	_, gitErr := cmd.CombinedOutput()
-
-	// Restore original file content (including unselected changes)
-	restoreErr := os.WriteFile(filePath, originalContent, 0644)
-
-	if gitErr != nil {
+	// Write the new content straight to the index (the working tree is left as is)
+	if err := git.StageContent(params.CurrentFile, params.RepoRoot, modifiedContent); err != nil {
 		if params.UpdateGlobalStatus != nil {
 			params.UpdateGlobalStatus("Failed to stage changes", "tomato")
-			if restoreErr != nil {
-				params.UpdateGlobalStatus("Critical: Failed to restore file!", "tomato")
-			}
-		}
-		return nil, gitErr
-	}
-
-	if restoreErr != nil {
-		if params.UpdateGlobalStatus != nil {
-			params.UpdateGlobalStatus("Warning: Failed to restore unstaged changes", "yellow")
 		}
-		return nil, restoreErr
+		return nil, err
 	}
 
 	// Re-fetch the diff
@@ -170,6 +162,15 @@ func CommandA(params CommandAParams) (*CommandAResult, error) {
 	return result, nil
 }
 
+// prepareUntrackedForStaging adds an untracked file with intent-to-add and
+// returns its diff against the (empty) index entry
+func prepareUntrackedForStaging(filePath string, repoRoot string) (string, error) {
+	if err := git.AddIntentToAdd(filePath, repoRoot); err != nil {
+		return "", err
+	}
+	return git.GetFileDiff(filePath, repoRoot)
+}
+
 // calculateNewCursorPosition calculates the recommended cursor position after staging
 func calculateNewCursorPosition(oldDiffText, newDiffText string, selectStart, selectEnd int) int {
 	if len(strings.TrimSpace(newDiffText)) == 0 {
@@ -197,14 +198,10 @@ func calculateNewCursorPosition(oldDiffText, newDiffText string, selectStart, se
 
 // commandAUnstage handles unstaging selected lines from staged diff
 func commandAUnstage(params CommandAParams) (*CommandAResult, error) {
-	// Build file path
-	filePath := filepath.Join(params.RepoRoot, params.CurrentFile)
-
-	// Save current file content
-	originalContent, err := os.ReadFile(filePath)
-	if err != nil {
+	// The staged file must exist in the index
+	if _, err := git.GetIndexFileMode(params.CurrentFile, params.RepoRoot); err != nil {
 		if params.UpdateGlobalStatus != nil {
-			params.UpdateGlobalStatus("Failed to read file", "tomato")
+			params.UpdateGlobalStatus("Failed to read file from index", "tomato")
 		}
 		return nil, err
 	}
@@ -258,38 +255,12 @@ func commandAUnstage(params CommandAParams) (*CommandAResult, error) {
 		return nil, err
 	}
 
-	// Temporarily overwrite file with selected changes excluded
-	err = os.WriteFile(filePath, []byte(modifiedContent), 0644)
-	if err != nil {
-		if params.UpdateGlobalStatus != nil {
-			params.UpdateGlobalStatus("Failed to write file", "tomato")
-		}
-		return nil, err
-	}
-
-	// Run git add (only unselected changes remain staged = selected changes become unstaged)
-	cmd := exec.Command("git", "-c", "core.quotepath=false", "add", params.CurrentFile)
-	cmd.Dir = params.RepoRoot
-	_, gitErr := cmd.CombinedOutput()
-
-	// Restore original file content
-	restoreErr := os.WriteFile(filePath, originalContent, 0644)
-
-	if gitErr != nil {
+	// Only unselected changes remain staged = selected changes become unstaged
+	if err := git.StageContent(params.CurrentFile, params.RepoRoot, modifiedContent); err != nil {
 		if params.UpdateGlobalStatus != nil {
 			params.UpdateGlobalStatus("Failed to unstage changes", "tomato")
-			if restoreErr != nil {
-				params.UpdateGlobalStatus("Critical: Failed to restore file!", "tomato")
-			}
 		}
-		return nil, gitErr
-	}
-
-	if restoreErr != nil {
-		if params.UpdateGlobalStatus != nil {
-			params.UpdateGlobalStatus("Warning: Failed to restore file", "yellow")
-		}
-		return nil, restoreErr
+		return nil, err
 	}
 
 	// Re-fetch the staged diff
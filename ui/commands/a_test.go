@@ -6,6 +6,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+	"github.com/sukechannnn/giff/util"
 )
 
 func TestCommandA(t *testing.T) {
@@ -551,3 +554,161 @@ func TestCommandA_UnstageIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestCommandA_LeavesWorkingTreeUntouched(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "giff-index-only-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+		{"config", "core.fileMode", "true"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	// Commit an executable script
+	testFile := filepath.Join(tmpDir, "run.sh")
+	if err := os.WriteFile(testFile, []byte("line1\nline2\nline3\nline4\nline5\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", "run.sh"}, {"commit", "-m", "initial"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	modifiedContent := "line1\nmodified line2\nline3\nmodified line4\nline5\n"
+	if err := os.WriteFile(testFile, []byte(modifiedContent), 0755); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.Stat(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diffCmd := exec.Command("git", "diff", "run.sh")
+	diffCmd.Dir = tmpDir
+	diffOutput, err := diffCmd.Output()
+	if err != nil {
+		t.Fatal("Failed to get diff:", err)
+	}
+
+	result, err := CommandA(CommandAParams{
+		SelectStart:     2,
+		SelectEnd:       3,
+		CurrentFile:     "run.sh",
+		CurrentStatus:   "unstaged",
+		CurrentDiffText: string(diffOutput),
+		RepoRoot:        tmpDir,
+	})
+	if err != nil || result == nil || !result.Success {
+		t.Fatalf("CommandA failed: result=%v, err=%v", result, err)
+	}
+
+	// Working tree content and mode must be unchanged
+	content, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != modifiedContent {
+		t.Errorf("Working tree file was modified: %q", string(content))
+	}
+	after, err := os.Stat(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Mode() != before.Mode() || !after.ModTime().Equal(before.ModTime()) {
+		t.Errorf("Working tree file was rewritten: mode %v -> %v", before.Mode(), after.Mode())
+	}
+
+	// The index entry keeps the executable bit
+	lsCmd := exec.Command("git", "ls-files", "--stage", "run.sh")
+	lsCmd.Dir = tmpDir
+	lsOutput, err := lsCmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(lsOutput), "100755 ") {
+		t.Errorf("Expected index mode 100755, got %q", string(lsOutput))
+	}
+
+	stagedCmd := exec.Command("git", "diff", "--cached", "run.sh")
+	stagedCmd.Dir = tmpDir
+	stagedOutput, err := stagedCmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(stagedOutput), "+modified line2") || strings.Contains(string(stagedOutput), "modified line4") {
+		t.Errorf("Unexpected staged diff:\n%s", string(stagedOutput))
+	}
+}
+
+func TestCommandA_UntrackedFile(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+
+	runGit("commit", "--allow-empty", "-m", "initial")
+
+	content := "line1\nline2\nline3\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "new.txt"), []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same synthesized diff the untracked file view shows
+	diffText := util.FormatAsAddedLines(content, "new.txt")
+
+	// Selecting only the "new file mode" line stages nothing and must not
+	// register the file with intent-to-add
+	if _, err := CommandA(CommandAParams{
+		SelectStart:     0,
+		SelectEnd:       0,
+		CurrentFile:     "new.txt",
+		CurrentStatus:   "untracked",
+		CurrentDiffText: diffText,
+		RepoRoot:        tmpDir,
+	}); err != nil {
+		t.Fatalf("CommandA failed: %v", err)
+	}
+	if status := runGit("status", "--porcelain", "new.txt"); status != "?? new.txt" {
+		t.Errorf("status after empty selection = %q, want %q", status, "?? new.txt")
+	}
+
+	// Display lines: "new file mode 100644", "+line1", "+line2", "+line3", "+"
+	result, err := CommandA(CommandAParams{
+		SelectStart:     1,
+		SelectEnd:       2,
+		CurrentFile:     "new.txt",
+		CurrentStatus:   "untracked",
+		CurrentDiffText: diffText,
+		RepoRoot:        tmpDir,
+	})
+	if err != nil {
+		t.Fatalf("CommandA failed: %v", err)
+	}
+	if result == nil || result.ShouldUpdate {
+		t.Fatalf("Expected remaining unstaged lines, got %+v", result)
+	}
+
+	if staged := runGit("show", ":new.txt"); staged != "line1\nline2" {
+		t.Errorf("staged content = %q, want %q", staged, "line1\nline2")
+	}
+	if mode := strings.Fields(runGit("ls-files", "--stage", "new.txt"))[0]; mode != "100755" {
+		t.Errorf("staged mode = %s, want 100755", mode)
+	}
+	if status := runGit("status", "--porcelain", "new.txt"); status != "AM new.txt" {
+		t.Errorf("status = %q, want %q", status, "AM new.txt")
+	}
+	if !strings.Contains(result.NewDiffText, "+line3") || strings.Contains(result.NewDiffText, "+line1") {
+		t.Errorf("unexpected remaining diff:\n%s", result.NewDiffText)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+
+	"github.com/sukechannnn/giff/git"
 )
 
 // CommandDParams contains parameters for commandD function
@@ -12,6 +14,7 @@ type CommandDParams struct {
 	CurrentFile   string
 	CurrentStatus string
 	RepoRoot      string
+	DiscardLog    *git.DiscardLog // if non-nil, the content is saved here before discarding
 }
 
 // CommandD handles the 'd' command for discarding changes
@@ -26,7 +29,19 @@ func CommandD(params CommandDParams) error {
 		return fmt.Errorf("No file selected")
 	}
 
-	// Delete the file if it is untracked
+	// A nested repository's .git cannot be saved, so it is never deleted
+	if params.CurrentStatus == "untracked" && git.HasNestedRepository(params.RepoRoot, params.CurrentFile) {
+		return fmt.Errorf("Cannot delete %s: it contains a nested git repository", params.CurrentFile)
+	}
+
+	// Keep the content so the discard can be restored later
+	if params.DiscardLog != nil {
+		if err := params.DiscardLog.Save(params.RepoRoot, params.CurrentFile); err != nil {
+			return err
+		}
+	}
+
+	// Delete the file (or directory) if it is untracked
 	if params.CurrentStatus == "untracked" {
 		fullPath := filepath.Join(params.RepoRoot, params.CurrentFile)
 		if err := os.RemoveAll(fullPath); err != nil {
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sukechannnn/giff/git"
+	"github.com/sukechannnn/giff/git/gittest"
+)
+
+func TestCommandD_UntrackedDirectory(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("README.md", "readme\n")
+	runGit("add", "README.md")
+	runGit("commit", "-m", "initial")
+
+	// A directory holding a nested repository is refused and left intact
+	writeFile("vendor/lib/lib.go", "package lib\n")
+	runGit("init", "-q", "vendor/lib")
+	log := &git.DiscardLog{}
+	err := CommandD(CommandDParams{CurrentFile: "vendor", CurrentStatus: "untracked", RepoRoot: tmpDir, DiscardLog: log})
+	if err == nil || !strings.Contains(err.Error(), "nested git repository") {
+		t.Fatalf("CommandD on a nested repository = %v, want it refused", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "vendor/lib/.git")); err != nil {
+		t.Errorf("nested .git was removed: %v", err)
+	}
+	if len(log.Recent()) != 0 {
+		t.Errorf("refused discard was recorded: %+v", log.Recent())
+	}
+
+	// A plain untracked directory is deleted and can be restored
+	writeFile("tmp/a.txt", "a\n")
+	if err := CommandD(CommandDParams{CurrentFile: "tmp", CurrentStatus: "untracked", RepoRoot: tmpDir, DiscardLog: log}); err != nil {
+		t.Fatalf("CommandD failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "tmp")); !os.IsNotExist(err) {
+		t.Errorf("tmp/ still exists: %v", err)
+	}
+	if err := log.Restore(tmpDir, log.Recent()[0]); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if content, err := os.ReadFile(filepath.Join(tmpDir, "tmp/a.txt")); err != nil || string(content) != "a\n" {
+		t.Errorf("restored tmp/a.txt = %q, %v", content, err)
+	}
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sukechannnn/giff/git"
+)
+
+// CommandDiscardLinesParams contains parameters for CommandDiscardLines
+type CommandDiscardLinesParams struct {
+	SelectStart     int // display index (excluding diff headers)
+	SelectEnd       int
+	CurrentFile     string
+	CurrentStatus   string
+	CurrentDiffText string
+	RepoRoot        string
+	DiscardLog      *git.DiscardLog // if non-nil, the file is saved here before discarding
+}
+
+// CommandDiscardLinesResult contains the results from CommandDiscardLines execution
+type CommandDiscardLinesResult struct {
+	NewDiffText  string
+	ShouldUpdate bool // true if no unstaged changes remain
+	NewCursorPos int
+}
+
+// CommandDiscardLines reverts the selected lines of an unstaged diff in the working tree.
+// The index and the other changes are left untouched.
+func CommandDiscardLines(params CommandDiscardLinesParams) (*CommandDiscardLinesResult, error) {
+	if params.CurrentStatus != "unstaged" {
+		return nil, fmt.Errorf("Only unstaged changes can be discarded")
+	}
+	if params.SelectStart == -1 || params.SelectEnd == -1 || params.CurrentFile == "" || params.CurrentDiffText == "" {
+		return nil, fmt.Errorf("No lines selected")
+	}
+
+	mapping := MapDisplayToOriginalIdx(params.CurrentDiffText)
+	start, ok := mapping[params.SelectStart]
+	if !ok {
+		return nil, fmt.Errorf("No lines selected")
+	}
+	end, ok := mapping[params.SelectEnd]
+	if !ok {
+		end = start
+	}
+
+	// Make sure the selection contains actual changes
+	diffLines := strings.Split(params.CurrentDiffText, "\n")
+	hasChanges := false
+	for i := start; i <= end && i < len(diffLines); i++ {
+		if strings.HasPrefix(diffLines[i], "+") || strings.HasPrefix(diffLines[i], "-") {
+			hasChanges = true
+			break
+		}
+	}
+	if !hasChanges {
+		return nil, fmt.Errorf("No changes to discard in the selection")
+	}
+
+	modifiedContent, err := git.RevertSelectedChangesFromWorkingTree(params.CurrentFile, params.RepoRoot, params.CurrentDiffText, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the content so the discard can be restored later
+	if params.DiscardLog != nil {
+		if err := params.DiscardLog.Save(params.RepoRoot, params.CurrentFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := git.WriteWorkingTreeContent(params.CurrentFile, params.RepoRoot, modifiedContent); err != nil {
+		return nil, err
+	}
+
+	newDiffText, _ := git.GetFileDiff(params.CurrentFile, params.RepoRoot)
+	return &CommandDiscardLinesResult{
+		NewDiffText:  newDiffText,
+		ShouldUpdate: len(strings.TrimSpace(newDiffText)) == 0,
+		NewCursorPos: calculateNewCursorPosition(params.CurrentDiffText, newDiffText, params.SelectStart, params.SelectEnd),
+	}, nil
+}
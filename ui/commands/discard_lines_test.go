@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sukechannnn/giff/git/gittest"
+
+	"github.com/sukechannnn/giff/git"
+)
+
+func TestCommandDiscardLines(t *testing.T) {
+	t.Run("rejects staged changes", func(t *testing.T) {
+		_, err := CommandDiscardLines(CommandDiscardLinesParams{
+			SelectStart:     0,
+			SelectEnd:       0,
+			CurrentFile:     "test.txt",
+			CurrentStatus:   "staged",
+			CurrentDiffText: "some diff",
+		})
+		if err == nil || !strings.Contains(err.Error(), "Only unstaged changes") {
+			t.Errorf("Expected 'Only unstaged changes' error, got %v", err)
+		}
+	})
+
+	t.Run("rejects an empty selection", func(t *testing.T) {
+		_, err := CommandDiscardLines(CommandDiscardLinesParams{
+			SelectStart:     -1,
+			SelectEnd:       -1,
+			CurrentFile:     "test.txt",
+			CurrentStatus:   "unstaged",
+			CurrentDiffText: "some diff",
+		})
+		if err == nil {
+			t.Error("Expected error for empty selection")
+		}
+	})
+}
+
+func TestCommandDiscardLines_Integration(t *testing.T) {
+	tmpDir, runGit := gittest.NewTestRepo(t)
+
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("line1\nline2\nline3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit("add", "test.txt")
+	runGit("commit", "-m", "initial")
+
+	// Stage one change, then make two more in the working tree
+	if err := os.WriteFile(testFile, []byte("staged1\nline2\nline3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit("add", "test.txt")
+	if err := os.WriteFile(testFile, []byte("staged1\nmodified2\nline3\nnew4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stagedBefore := runGit("diff", "--cached")
+
+	diffText := runGit("diff", "test.txt")
+	// Display lines: " staged1", "-line2", "+modified2", " line3", "+new4"
+	log := git.NewDiscardLog()
+	result, err := CommandDiscardLines(CommandDiscardLinesParams{
+		SelectStart:     1,
+		SelectEnd:       2,
+		CurrentFile:     "test.txt",
+		CurrentStatus:   "unstaged",
+		CurrentDiffText: diffText,
+		RepoRoot:        tmpDir,
+		DiscardLog:      log,
+	})
+	if err != nil {
+		t.Fatalf("CommandDiscardLines failed: %v", err)
+	}
+	if result.ShouldUpdate {
+		t.Error("Expected unstaged changes to remain")
+	}
+
+	content, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "staged1\nline2\nline3\nnew4\n"; string(content) != want {
+		t.Errorf("working tree = %q, want %q", content, want)
+	}
+	if stagedAfter := runGit("diff", "--cached"); stagedAfter != stagedBefore {
+		t.Errorf("index changed:\n%s\nwant:\n%s", stagedAfter, stagedBefore)
+	}
+	if len(log.Recent()) != 1 {
+		t.Errorf("Expected the discarded content to be saved, got %d records", len(log.Recent()))
+	}
+
+	// Context lines only
+	if _, err := CommandDiscardLines(CommandDiscardLinesParams{
+		SelectStart:     0,
+		SelectEnd:       0,
+		CurrentFile:     "test.txt",
+		CurrentStatus:   "unstaged",
+		CurrentDiffText: result.NewDiffText,
+		RepoRoot:        tmpDir,
+	}); err == nil {
+		t.Error("Expected error when only context lines are selected")
+	}
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+	"github.com/sukechannnn/giff/util"
+)
+
+// commitOutputHeight is the height of the panel showing why a commit was rejected
+const commitOutputHeight = 10
+
+// commitAreaTitle returns the title of the commit message area with the state
+// of the commit options and the keys that toggle them
+func commitAreaTitle(label string, options git.CommitOptions) string {
+	onOff := func(enabled bool) string {
+		if enabled {
+			return "[green]on[-]"
+		}
+		return "off"
+	}
+	return label + "  │  M-s signoff: " + onOff(options.Signoff) +
+		"  M-n no-verify: " + onOff(options.NoVerify) +
+		"  M-g sign: " + onOff(options.GPGSign)
+}
+
+// toggleCommitOption flips the option bound to an Alt+key in the commit
+// message area and reports whether the key was one of them
+func toggleCommitOption(options *git.CommitOptions, key rune) bool {
+	switch key {
+	case 's':
+		options.Signoff = !options.Signoff
+	case 'n':
+		options.NoVerify = !options.NoVerify
+	case 'g':
+		options.GPGSign = !options.GPGSign
+	default:
+		return false
+	}
+	return true
+}
+
+// newCommitOutputView creates the scrollable panel that shows the output of
+// a rejected commit, such as the messages of the pre-commit and commit-msg
+// hooks. onBack is called with Esc or Tab.
+func newCommitOutputView(onBack func()) *tview.TextView {
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true)
+	view.SetBorder(true)
+	view.SetTitle(" Commit rejected (j/k: scroll, Esc: back to message) ")
+	view.SetTitleAlign(tview.AlignLeft)
+	view.SetTitleColor(tcell.ColorTomato)
+	view.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc || event.Key() == tcell.KeyTab {
+			onBack()
+			return nil
+		}
+		return event
+	})
+	return view
+}
+
+// commitRejectionText strips the "failed to commit:" prefix from a commit
+// error, leaving git's and the hooks' output
+func commitRejectionText(err error) string {
+	text := err.Error()
+	for _, prefix := range []string{"failed to commit: ", "failed to amend commit: "} {
+		text = strings.TrimPrefix(text, prefix)
+	}
+	return tview.Escape(text)
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/sukechannnn/giff/git"
+)
+
+// conflictBlockIndex returns the index of the conflict block (hunk) containing
+// the given diff line index (excluding headers), or -1 if there is none
+func conflictBlockIndex(diffText string, lineIdx int) int {
+	_, _, hunks := numberDiffLines(diffText)
+	for i, h := range hunks {
+		if lineIdx >= h.Start && lineIdx <= h.End {
+			return i
+		}
+	}
+	return -1
+}
+
+// resolveConflictAtCursor applies a resolution to the conflict block under the cursor.
+// Files without conflict markers (e.g. modify/delete conflicts) take the whole side instead.
+func resolveConflictAtCursor(ctx *DiffViewContext, resolution git.ConflictResolution) {
+	file := *ctx.currentFile
+	content, err := os.ReadFile(filepath.Join(ctx.repoRoot, file))
+	if err != nil && !os.IsNotExist(err) {
+		ctx.updateGlobalStatus("Failed to read file", "tomato")
+		return
+	}
+
+	if len(git.ParseConflictBlocks(string(content))) == 0 {
+		if resolution == git.TakeBoth {
+			ctx.updateGlobalStatus("This conflict can only take ours or theirs", "yellow")
+			return
+		}
+		if err := git.TakeConflictSide(file, ctx.repoRoot, resolution); err != nil {
+			ctx.updateGlobalStatus("Failed to resolve conflict: "+err.Error(), "tomato")
+			return
+		}
+	} else {
+		if *ctx.isSplitView {
+			ctx.updateGlobalStatus("Switch to unified view (s) to resolve conflict blocks", "yellow")
+			return
+		}
+		displayMapping := MapUnifiedDisplayToOriginalIdx(*ctx.currentDiffText, ctx.foldState, file, ctx.repoRoot)
+		lineIdx, ok := displayMapping[*ctx.cursorY]
+		if !ok {
+			return
+		}
+		blockIndex := conflictBlockIndex(*ctx.currentDiffText, lineIdx)
+		if blockIndex < 0 {
+			return
+		}
+		if err := git.ResolveConflict(file, ctx.repoRoot, blockIndex, resolution); err != nil {
+			ctx.updateGlobalStatus("Failed to resolve conflict: "+err.Error(), "tomato")
+			return
+		}
+	}
+
+	// Reload the conflict view
+	InvalidateUnifiedContentCache()
+	ctx.updateCurrentDiffText(file, "conflicted", ctx.repoRoot, ctx.currentDiffText, *ctx.ignoreWhitespace)
+	lineCount := GetUnifiedViewLineCount(*ctx.currentDiffText, ctx.foldState, file, ctx.repoRoot)
+	if *ctx.cursorY >= lineCount {
+		*ctx.cursorY = lineCount - 1
+	}
+	if *ctx.cursorY < 0 {
+		*ctx.cursorY = 0
+	}
+	if ctx.viewUpdater != nil {
+		ctx.viewUpdater.UpdateWithCursor(*ctx.currentDiffText, *ctx.cursorY)
+	}
+
+	remaining, _ := os.ReadFile(filepath.Join(ctx.repoRoot, file))
+	if len(git.ParseConflictBlocks(string(remaining))) == 0 {
+		ctx.updateGlobalStatus("All conflicts resolved. Press A to mark as resolved", "forestgreen")
+	} else {
+		ctx.updateGlobalStatus("Conflict block resolved", "forestgreen")
+	}
+}
+
+// markConflictResolved stages a conflicted file once all its markers are gone
+func markConflictResolved(file, repoRoot string, updateStatus func(string, string)) bool {
+	if err := git.MarkResolved(file, repoRoot); err != nil {
+		updateStatus("Cannot mark as resolved: "+err.Error(), "tomato")
+		return false
+	}
+	updateStatus("Marked "+file+" as resolved", "forestgreen")
+	return true
+}
@@ -26,6 +26,7 @@ type DiffViewContext struct {
 	unifiedViewFlex *tview.Flex
 	contentFlex     *tview.Flex
 	app             *tview.Application
+	mainView        tview.Primitive // view shown behind confirmation modals
 
 	// State
 	cursorY               *int
@@ -49,8 +50,9 @@ type DiffViewContext struct {
 	patchPath string
 
 	// Key handling state
-	gPressed  *bool
-	lastGTime *time.Time
+	gPressed       *bool
+	lastGTime      *time.Time
+	pendingBracket rune // ']' or '[' waiting for 'c' (hunk navigation)
 
 	// View updater
 	viewUpdater DiffViewUpdater
@@ -263,8 +265,43 @@ func SetupDiffViewKeyBindings(ctx *DiffViewContext) {
 			// Ctrl+Y: scroll up one line
 			scrollDiffView(ctx, -1)
 			return nil
+		case tcell.KeyCtrlR:
+			if ctx.readOnly {
+				return nil
+			}
+			redoLastAction(ctx.repoRoot, ctx.onUpdate)
+			return nil
 		case tcell.KeyRune:
+			// "]c" / "[c": jump to the next / previous hunk
+			if ctx.pendingBracket != 0 {
+				bracket := ctx.pendingBracket
+				ctx.pendingBracket = 0
+				if event.Rune() == 'c' {
+					if bracket == ']' {
+						moveToHunk(ctx, 1)
+					} else {
+						moveToHunk(ctx, -1)
+					}
+					return nil
+				}
+			}
+
 			switch event.Rune() {
+			case ']', '[':
+				ctx.pendingBracket = event.Rune()
+				return nil
+			case 'S':
+				if ctx.readOnly || *ctx.currentStatus == "conflicted" {
+					return nil
+				}
+				stageHunkAtCursor(ctx)
+				return nil
+			case 'd':
+				if ctx.readOnly {
+					return nil
+				}
+				discardAtCursor(ctx)
+				return nil
 			case 's':
 				// Toggle split view
 				*ctx.isSplitView = !*ctx.isSplitView
@@ -601,7 +638,8 @@ func SetupDiffViewKeyBindings(ctx *DiffViewContext) {
 				if ctx.readOnly {
 					return nil
 				}
-				ctx.updateGlobalStatus("undo is not implemented!", "tomato")
+				undoLastAction(ctx.repoRoot, ctx.onUpdate)
+				return nil
 			case 'v':
 				if ctx.readOnly {
 					return nil
@@ -628,6 +666,22 @@ func SetupDiffViewKeyBindings(ctx *DiffViewContext) {
 					}
 				}
 				return nil
+			case 'B':
+				if ctx.readOnly || *ctx.currentStatus == "untracked" {
+					return nil
+				}
+				openBlameAtCursor(ctx)
+				return nil
+			case 'h':
+				if ctx.readOnly || *ctx.currentFile == "" {
+					return nil
+				}
+				var focusView tview.Primitive = ctx.diffView
+				if *ctx.isSplitView {
+					focusView = ctx.splitViewFlex
+				}
+				showFileHistory(ctx.app, ctx.repoRoot, *ctx.currentFile, ctx.mainView, focusView, ctx.updateGlobalStatus)
+				return nil
 			case 'c':
 				// Open file in VSCode
 				if *ctx.currentFile != "" {
@@ -665,8 +719,20 @@ func SetupDiffViewKeyBindings(ctx *DiffViewContext) {
 					}
 				}
 				return nil
+			case '<', '>', 'b':
+				if ctx.readOnly || *ctx.currentStatus != "conflicted" {
+					return nil
+				}
+				resolution := git.TakeOurs
+				if event.Rune() == '>' {
+					resolution = git.TakeTheirs
+				} else if event.Rune() == 'b' {
+					resolution = git.TakeBoth
+				}
+				resolveConflictAtCursor(ctx, resolution)
+				return nil
 			case 'a':
-				if ctx.readOnly {
+				if ctx.readOnly || *ctx.currentStatus == "conflicted" {
 					return nil
 				}
 				// Call commandA function
@@ -685,100 +751,20 @@ func SetupDiffViewKeyBindings(ctx *DiffViewContext) {
 					}
 				}
 
-				params := commands.CommandAParams{
-					SelectStart:        selectStart,
-					SelectEnd:          selectEnd,
-					CurrentFile:        *ctx.currentFile,
-					CurrentStatus:      *ctx.currentStatus,
-					CurrentDiffText:    *ctx.currentDiffText,
-					RepoRoot:           ctx.repoRoot,
-					UpdateGlobalStatus: ctx.updateGlobalStatus,
-					IsSplitView:        *ctx.isSplitView,
-				}
-
-				result, err := commands.CommandA(params)
-				if err != nil {
-					return nil
-				}
-				if result == nil {
+				runCommandA(ctx, selectStart, selectEnd)
+				return nil
+			case 'A':
+				if ctx.readOnly {
 					return nil
 				}
-
-				// Apply results
-				*ctx.currentDiffText = result.NewDiffText
-
-				// Deselect and update cursor position
-				*ctx.isSelecting = false
-				*ctx.selectStart = -1
-				*ctx.selectEnd = -1
-
-				// Cursor position boundary check
-				newCursorPos := result.NewCursorPos
-				if len(strings.TrimSpace(*ctx.currentDiffText)) > 0 {
-					coloredDiff := ColorizeDiff(*ctx.currentDiffText)
-					diffLines := util.SplitLines(coloredDiff)
-					maxLines := len(diffLines) - 1
-					if maxLines < 0 {
-						maxLines = 0
-					}
-					if newCursorPos > maxLines {
-						newCursorPos = maxLines
-					}
-					if newCursorPos < 0 {
-						newCursorPos = 0
-					}
-				} else {
-					newCursorPos = 0
-				}
-				*ctx.cursorY = newCursorPos
-
-				// Redraw
-				if ctx.viewUpdater != nil {
-					ctx.viewUpdater.UpdateWithCursor(*ctx.currentDiffText, *ctx.cursorY)
-				}
-
-				// Internally update file list
-				ctx.refreshFileList()
-
-				// If diff remains
-				if !result.ShouldUpdate {
-					// Save currently selected file and status
-					var currentlySelectedFile string
-					var currentlySelectedStatus string
-					if *ctx.currentSelection >= 0 && *ctx.currentSelection < len(*ctx.fileList) {
-						fileEntry := (*ctx.fileList)[*ctx.currentSelection]
-						currentlySelectedFile = fileEntry.Path
-						currentlySelectedStatus = fileEntry.StageStatus
-					}
-
-					// Redraw file list
-					ctx.updateFileListView()
-
-					// Restore selection position (search by both filename and status)
-					newSelection := -1
-					for i, fileEntry := range *ctx.fileList {
-						if fileEntry.Path == currentlySelectedFile && fileEntry.StageStatus == currentlySelectedStatus {
-							newSelection = i
-							break
+				if *ctx.currentStatus == "conflicted" {
+					if markConflictResolved(*ctx.currentFile, ctx.repoRoot, ctx.updateGlobalStatus) {
+						*ctx.preferUnstagedSection = true
+						*ctx.savedTargetFile = ""
+						if ctx.onUpdate != nil {
+							ctx.onUpdate()
 						}
 					}
-					if newSelection >= 0 {
-						*ctx.currentSelection = newSelection
-					} else if *ctx.currentSelection >= len(*ctx.fileList) {
-						*ctx.currentSelection = len(*ctx.fileList) - 1
-					}
-
-					// Update file list again if selection position changed
-					ctx.updateFileListView()
-				} else {
-					// If diff is gone, fully update
-					if ctx.onUpdate != nil {
-						ctx.onUpdate()
-					}
-				}
-				return nil
-			case 'A':
-				if ctx.readOnly {
 					return nil
 				}
 				// Stage/unstage the current file
@@ -791,7 +777,11 @@ func SetupDiffViewKeyBindings(ctx *DiffViewContext) {
 					}
 					cmd.Dir = ctx.repoRoot
 
-					err := cmd.Run()
+					description := "stage " + *ctx.currentFile
+					if *ctx.currentStatus == "staged" {
+						description = "unstage " + *ctx.currentFile
+					}
+					err := journalAction(ctx.repoRoot, description, nil, cmd.Run)
 					if err == nil {
 						wasStaged := (*ctx.currentStatus == "staged")
 
@@ -1088,3 +1078,113 @@ func moveToPrevMatch(ctx *DiffViewContext) {
 	}
 	ctx.setGlobalStatusText(fmt.Sprintf("[white]/%s [%d/%d][-]", tview.Escape(*ctx.searchQuery), *ctx.searchMatchIndex+1, len(matches)))
 }
+
+// runCommandA stages (or unstages) the diff lines selectStart..selectEnd
+// (indices exclude diff headers and fold indicators) and refreshes the views
+func runCommandA(ctx *DiffViewContext, selectStart, selectEnd int) {
+	params := commands.CommandAParams{
+		SelectStart:        selectStart,
+		SelectEnd:          selectEnd,
+		CurrentFile:        *ctx.currentFile,
+		CurrentStatus:      *ctx.currentStatus,
+		CurrentDiffText:    *ctx.currentDiffText,
+		RepoRoot:           ctx.repoRoot,
+		UpdateGlobalStatus: ctx.updateGlobalStatus,
+		IsSplitView:        *ctx.isSplitView,
+	}
+
+	description := "stage lines in " + *ctx.currentFile
+	if *ctx.currentStatus == "staged" {
+		description = "unstage lines in " + *ctx.currentFile
+	}
+	var result *commands.CommandAResult
+	err := journalAction(ctx.repoRoot, description, nil, func() error {
+		var err error
+		result, err = commands.CommandA(params)
+		return err
+	})
+	if err != nil || result == nil {
+		return
+	}
+
+	// Apply results
+	*ctx.currentDiffText = result.NewDiffText
+
+	// A partially staged untracked file is now tracked, with the rest unstaged
+	if *ctx.currentStatus == "untracked" {
+		*ctx.currentStatus = "unstaged"
+	}
+
+	// Deselect and update cursor position
+	*ctx.isSelecting = false
+	*ctx.selectStart = -1
+	*ctx.selectEnd = -1
+
+	// Cursor position boundary check
+	newCursorPos := result.NewCursorPos
+	if len(strings.TrimSpace(*ctx.currentDiffText)) > 0 {
+		coloredDiff := ColorizeDiff(*ctx.currentDiffText)
+		diffLines := util.SplitLines(coloredDiff)
+		maxLines := len(diffLines) - 1
+		if maxLines < 0 {
+			maxLines = 0
+		}
+		if newCursorPos > maxLines {
+			newCursorPos = maxLines
+		}
+		if newCursorPos < 0 {
+			newCursorPos = 0
+		}
+	} else {
+		newCursorPos = 0
+	}
+	*ctx.cursorY = newCursorPos
+
+	// Redraw
+	if ctx.viewUpdater != nil {
+		ctx.viewUpdater.UpdateWithCursor(*ctx.currentDiffText, *ctx.cursorY)
+	}
+
+	// Internally update file list
+	ctx.refreshFileList()
+
+	// If diff remains
+	if !result.ShouldUpdate {
+		// Save currently selected file and status
+		var currentlySelectedFile string
+		var currentlySelectedStatus string
+		if *ctx.currentSelection >= 0 && *ctx.currentSelection < len(*ctx.fileList) {
+			fileEntry := (*ctx.fileList)[*ctx.currentSelection]
+			currentlySelectedFile = fileEntry.Path
+			currentlySelectedStatus = fileEntry.StageStatus
+			if currentlySelectedFile == *ctx.currentFile {
+				currentlySelectedStatus = *ctx.currentStatus
+			}
+		}
+
+		// Redraw file list
+		ctx.updateFileListView()
+
+		// Restore selection position (search by both filename and status)
+		newSelection := -1
+		for i, fileEntry := range *ctx.fileList {
+			if fileEntry.Path == currentlySelectedFile && fileEntry.StageStatus == currentlySelectedStatus {
+				newSelection = i
+				break
+			}
+		}
+		if newSelection >= 0 {
+			*ctx.currentSelection = newSelection
+		} else if *ctx.currentSelection >= len(*ctx.fileList) {
+			*ctx.currentSelection = len(*ctx.fileList) - 1
+		}
+
+		// Update file list again if selection position changed
+		ctx.updateFileListView()
+	} else {
+		// If diff is gone, fully update
+		if ctx.onUpdate != nil {
+			ctx.onUpdate()
+		}
+	}
+}
@@ -344,8 +344,17 @@ func isLineSelected(index, start, end int) bool {
 
 // createLineNumberMapping creates line number mapping from diff text
 func createLineNumberMapping(diffText string) (map[int]int, map[int]int) {
+	oldLineMap, newLineMap, _ := numberDiffLines(diffText)
+	return oldLineMap, newLineMap
+}
+
+// numberDiffLines maps each displayed diff line to its old and new line
+// numbers, and returns the display lines of each hunk, which restarts the
+// numbering at its "@@" header
+func numberDiffLines(diffText string) (map[int]int, map[int]int, []diffHunk) {
 	oldLineMap := make(map[int]int)
 	newLineMap := make(map[int]int)
+	var hunks []diffHunk
 
 	lines := strings.Split(diffText, "\n")
 	displayLine := 0
@@ -371,6 +380,7 @@ func createLineNumberMapping(diffText string) (map[int]int, map[int]int) {
 				oldLineNum = oldStart
 				newLineNum = newStart
 				inHunk = true
+				hunks = append(hunks, diffHunk{Start: displayLine, End: displayLine - 1})
 			}
 			continue
 		}
@@ -394,8 +404,9 @@ func createLineNumberMapping(diffText string) (map[int]int, map[int]int) {
 			newLineNum++
 		}
 
+		hunks[len(hunks)-1].End = displayLine
 		displayLine++
 	}
 
-	return oldLineMap, newLineMap
+	return oldLineMap, newLineMap, hunks
 }
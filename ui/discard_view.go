@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+	"github.com/sukechannnn/giff/util"
+)
+
+// discardLog keeps the discards of the session (survives RootEditor rebuilds)
+var discardLog = git.NewDiscardLog()
+
+// maxPreviewLines limits how much of each discarded file is previewed
+const maxPreviewLines = 200
+
+// DiscardView lists recently discarded changes and restores them
+type DiscardView struct {
+	app         *tview.Application
+	listView    *tview.TextView
+	previewView *tview.TextView
+	statusView  *tview.TextView
+	flex        *tview.Flex
+	repoRoot    string
+	log         *git.DiscardLog
+	records     []git.DiscardRecord
+	currentLine int
+	restored    bool
+	onExit      func(restored bool)
+}
+
+// NewDiscardView creates a view of the discard log
+func NewDiscardView(app *tview.Application, repoRoot string, log *git.DiscardLog, onExit func(restored bool)) *DiscardView {
+	listView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	listView.SetBorder(true).SetTitle("Recently Discarded")
+	listView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	previewView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false).
+		SetScrollable(true)
+	previewView.SetBorder(true).SetTitle("Discarded Content")
+	previewView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	statusView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false).
+		SetText("j/k:move  Enter/r:restore  C-e/C-y:scroll preview  Esc:back  q:quit")
+	statusView.SetBorder(true)
+	statusView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	contentFlex := tview.NewFlex().
+		AddItem(listView, 0, FileListFlexRatio, true).
+		AddItem(previewView, 0, DiffViewFlexRatio, false)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(contentFlex, 0, 1, true).
+		AddItem(statusView, 3, 0, false)
+
+	dv := &DiscardView{
+		app:         app,
+		listView:    listView,
+		previewView: previewView,
+		statusView:  statusView,
+		flex:        flex,
+		repoRoot:    repoRoot,
+		log:         log,
+		onExit:      onExit,
+	}
+
+	dv.setupKeyBindings()
+	dv.reload()
+
+	return dv
+}
+
+// GetView returns the main flex view
+func (dv *DiscardView) GetView() tview.Primitive {
+	return dv.flex
+}
+
+// reload re-reads the discard log and redraws the list
+func (dv *DiscardView) reload() {
+	dv.records = dv.log.Recent()
+	if dv.currentLine >= len(dv.records) {
+		dv.currentLine = len(dv.records) - 1
+	}
+	if dv.currentLine < 0 {
+		dv.currentLine = 0
+	}
+	dv.updateList()
+	dv.updatePreview()
+}
+
+// updateList renders the discard records with the current one highlighted
+func (dv *DiscardView) updateList() {
+	if len(dv.records) == 0 {
+		dv.listView.SetText("Nothing discarded in this session")
+		return
+	}
+
+	var sb strings.Builder
+	for i, record := range dv.records {
+		line := fmt.Sprintf("%s  %s", record.Time.Format("15:04:05"), tview.Escape(record.Path))
+		if n := len(record.Files); n > 1 {
+			line += fmt.Sprintf(" (%d files)", n)
+		}
+		if i == dv.currentLine {
+			sb.WriteString("[white:blue]" + line + "[-:-]\n")
+		} else {
+			sb.WriteString(line + "\n")
+		}
+	}
+	dv.listView.SetText(sb.String())
+}
+
+// updatePreview shows the saved content of the selected record
+func (dv *DiscardView) updatePreview() {
+	dv.previewView.Clear()
+	if dv.currentLine >= len(dv.records) {
+		return
+	}
+
+	record := dv.records[dv.currentLine]
+	var sb strings.Builder
+	for _, path := range record.FilePaths() {
+		state := record.Files[path]
+		sb.WriteString("[yellow]" + tview.Escape(path) + "[-]\n")
+		if !state.Exists {
+			sb.WriteString("[gray](did not exist)[-]\n\n")
+			continue
+		}
+
+		cmd := exec.Command("git", "cat-file", "blob", state.Hash)
+		cmd.Dir = dv.repoRoot
+		content, err := cmd.Output()
+		if err != nil {
+			sb.WriteString("[red]Failed to read saved content[-]\n\n")
+			continue
+		}
+		lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+		if len(lines) > maxPreviewLines {
+			lines = append(lines[:maxPreviewLines], fmt.Sprintf("... %d more lines", len(lines)-maxPreviewLines))
+		}
+		for _, line := range lines {
+			sb.WriteString(tview.Escape(line) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	dv.previewView.SetText(sb.String())
+	dv.previewView.ScrollToBeginning()
+}
+
+// restoreSelected writes the selected record back into the working tree
+func (dv *DiscardView) restoreSelected() {
+	if dv.currentLine >= len(dv.records) {
+		return
+	}
+	record := dv.records[dv.currentLine]
+	if err := dv.log.Restore(dv.repoRoot, record); err != nil {
+		dv.statusView.SetText("[tomato]Failed to restore: " + tview.Escape(err.Error()) + "[-]")
+		return
+	}
+	dv.restored = true
+	dv.reload()
+	dv.statusView.SetText("[forestgreen]Restored " + tview.Escape(record.Path) + "[-]")
+}
+
+// setupKeyBindings configures keyboard navigation for the discard list
+func (dv *DiscardView) setupKeyBindings() {
+	dv.listView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			if dv.onExit != nil {
+				dv.onExit(dv.restored)
+			}
+			return nil
+		case tcell.KeyEnter:
+			dv.restoreSelected()
+			return nil
+		case tcell.KeyCtrlE:
+			row, col := dv.previewView.GetScrollOffset()
+			dv.previewView.ScrollTo(row+1, col)
+			return nil
+		case tcell.KeyCtrlY:
+			row, col := dv.previewView.GetScrollOffset()
+			if row > 0 {
+				dv.previewView.ScrollTo(row-1, col)
+			}
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'r':
+			dv.restoreSelected()
+			return nil
+		case 'j':
+			if dv.currentLine < len(dv.records)-1 {
+				dv.currentLine++
+				dv.updateList()
+				dv.updatePreview()
+			}
+			return nil
+		case 'k':
+			if dv.currentLine > 0 {
+				dv.currentLine--
+				dv.updateList()
+				dv.updatePreview()
+			}
+			return nil
+		case 'q':
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				os.Exit(0)
+			}()
+			dv.app.Stop()
+			return nil
+		}
+
+		return event
+	})
+}
@@ -0,0 +1,237 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+	"github.com/sukechannnn/giff/util"
+)
+
+var fileHistoryViewKeyMessage = "j/k:move  Enter:show diff  Esc:back  q:quit"
+
+var fileRevisionKeyMessage = "C-p/C-n:older/newer version  j/k:move  /:search  e:fold  s:split  V:select  C-y:copy  Esc:back  q:quit"
+
+// FileHistoryView lists the commits that touched a file and walks through its versions
+type FileHistoryView struct {
+	app         *tview.Application
+	listView    *tview.TextView
+	statusView  *tview.TextView
+	flex        *tview.Flex
+	repoRoot    string
+	revisions   []git.FileRevision
+	currentLine int
+	revision    *ReadOnlyDiffLayout // diff of the current version, while shown
+	onExit      func()
+}
+
+// NewFileHistoryView creates a view of the history of filePath, following renames
+func NewFileHistoryView(app *tview.Application, repoRoot, filePath string, onExit func()) (*FileHistoryView, error) {
+	revisions, err := git.GetFileHistory(repoRoot, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("no history for %s", filePath)
+	}
+
+	listView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	listView.SetBorder(true).SetTitle(tview.Escape("History: " + filePath))
+	listView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	statusView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false).
+		SetText(fileHistoryViewKeyMessage)
+	statusView.SetBorder(true)
+	statusView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(listView, 0, 1, true).
+		AddItem(statusView, 3, 0, false)
+
+	hv := &FileHistoryView{
+		app:        app,
+		listView:   listView,
+		statusView: statusView,
+		flex:       flex,
+		repoRoot:   repoRoot,
+		revisions:  revisions,
+		onExit:     onExit,
+	}
+
+	hv.setupKeyBindings()
+	hv.updateList()
+
+	return hv, nil
+}
+
+// GetView returns the main flex view
+func (hv *FileHistoryView) GetView() tview.Primitive {
+	return hv.flex
+}
+
+// updateList renders the commits with the current line highlighted
+func (hv *FileHistoryView) updateList() {
+	var sb strings.Builder
+	for i, revision := range hv.revisions {
+		rename := ""
+		if revision.OldPath != "" {
+			rename = " (renamed from " + tview.Escape(revision.OldPath) + ")"
+		}
+		if i == hv.currentLine {
+			sb.WriteString(fmt.Sprintf("[white:blue]%s %s (%s) <%s>%s[-:-]\n",
+				shortHash(revision.Hash), tview.Escape(revision.Subject), revision.Date, tview.Escape(revision.Author), rename))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("[yellow]%s[-] %s [green](%s)[-] [blue]<%s>[-][aqua]%s[-]\n",
+			shortHash(revision.Hash), tview.Escape(revision.Subject), revision.Date, tview.Escape(revision.Author), rename))
+	}
+	hv.listView.SetText(sb.String())
+
+	// Keep the current line visible
+	row, _ := hv.listView.GetScrollOffset()
+	_, _, _, height := hv.listView.GetInnerRect()
+	if hv.currentLine < row {
+		hv.listView.ScrollTo(hv.currentLine, 0)
+	} else if height > 0 && hv.currentLine >= row+height {
+		hv.listView.ScrollTo(hv.currentLine-height+1, 0)
+	}
+}
+
+// revisionFiles returns the file list entry of a version
+func revisionFiles(revision git.FileRevision) []FileEntry {
+	return []FileEntry{{
+		Path:         revision.Path,
+		StageStatus:  "commit",
+		ChangeStatus: normalizeCommitStatus(revision.Status),
+	}}
+}
+
+// revisionTitle describes a version in the file list title
+func (hv *FileHistoryView) revisionTitle() string {
+	revision := hv.revisions[hv.currentLine]
+	return tview.Escape(fmt.Sprintf("%d/%d %s %s", len(hv.revisions)-hv.currentLine, len(hv.revisions),
+		shortHash(revision.Hash), revision.Subject))
+}
+
+// showRevision shows the diff of the file in the selected commit
+func (hv *FileHistoryView) showRevision() {
+	if hv.currentLine >= len(hv.revisions) {
+		return
+	}
+
+	hv.revision = NewReadOnlyDiffLayout(ReadOnlyDiffLayoutParams{
+		App:           hv.app,
+		RepoRoot:      hv.repoRoot,
+		Files:         revisionFiles(hv.revisions[hv.currentLine]),
+		FileListTitle: hv.revisionTitle(),
+		StatusMessage: fileRevisionKeyMessage,
+		MainView:      hv.flex,
+		LoadDiff: func(filePath string, ignoreWhitespace bool) string {
+			diffText, err := git.GetFileRevisionDiff(hv.repoRoot, hv.revisions[hv.currentLine], ignoreWhitespace)
+			if err != nil {
+				return ""
+			}
+			return diffText
+		},
+		OnEsc: hv.backToList,
+	})
+
+	// Step through versions from either pane
+	hv.revision.View.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyCtrlP:
+			hv.stepRevision(1)
+			return nil
+		case tcell.KeyCtrlN:
+			hv.stepRevision(-1)
+			return nil
+		}
+		return event
+	})
+
+	hv.flex.Clear()
+	hv.flex.AddItem(hv.revision.View, 0, 1, true)
+	hv.app.SetFocus(hv.revision.FileListView)
+}
+
+// stepRevision moves to an older (1) or newer (-1) version without leaving the diff
+func (hv *FileHistoryView) stepRevision(direction int) {
+	next := hv.currentLine + direction
+	if next < 0 || next >= len(hv.revisions) {
+		return
+	}
+	hv.currentLine = next
+	hv.revision.FileListView.SetTitle(hv.revisionTitle())
+	hv.revision.SetFiles(revisionFiles(hv.revisions[hv.currentLine]))
+}
+
+// backToList returns to the commit list from the diff
+func (hv *FileHistoryView) backToList() {
+	hv.revision = nil
+	hv.flex.Clear()
+	hv.flex.AddItem(hv.listView, 0, 1, true)
+	hv.flex.AddItem(hv.statusView, 3, 0, false)
+	hv.updateList()
+	hv.app.SetFocus(hv.listView)
+}
+
+// setupKeyBindings configures keyboard navigation for the commit list
+func (hv *FileHistoryView) setupKeyBindings() {
+	hv.listView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			if hv.onExit != nil {
+				hv.onExit()
+			}
+			return nil
+		case tcell.KeyEnter:
+			hv.showRevision()
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'j':
+			if hv.currentLine < len(hv.revisions)-1 {
+				hv.currentLine++
+				hv.updateList()
+			}
+			return nil
+		case 'k':
+			if hv.currentLine > 0 {
+				hv.currentLine--
+				hv.updateList()
+			}
+			return nil
+		case 'q':
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				os.Exit(0)
+			}()
+			hv.app.Stop()
+			return nil
+		}
+
+		return event
+	})
+}
+
+// showFileHistory opens the history of filePath over mainView and returns focus to returnFocus
+func showFileHistory(app *tview.Application, repoRoot, filePath string, mainView, returnFocus tview.Primitive, updateStatus func(string, string)) {
+	historyView, err := NewFileHistoryView(app, repoRoot, filePath, func() {
+		app.SetRoot(mainView, true)
+		app.SetFocus(returnFocus)
+	})
+	if err != nil {
+		updateStatus(err.Error(), "tomato")
+		return
+	}
+	app.SetRoot(historyView.GetView(), true)
+}
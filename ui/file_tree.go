@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -153,10 +154,10 @@ func renderFileTree(
 	fileInfos []git.FileInfo,
 	collapseState *DirCollapseState,
 ) {
-	// Build status map for O(1) lookup
-	statusMap := make(map[string]string, len(fileInfos))
+	// Build file info map for O(1) lookup
+	infoMap := make(map[string]git.FileInfo, len(fileInfos))
 	for _, fi := range fileInfos {
-		statusMap[fi.Path] = fi.ChangeStatus
+		infoMap[fi.Path] = fi
 	}
 	renderFileTreeForGitFiles(
 		node,
@@ -172,7 +173,7 @@ func renderFileTree(
 		currentLine,
 		fileInfos,
 		collapseState,
-		statusMap,
+		infoMap,
 	)
 }
 
@@ -207,14 +208,36 @@ func BuildFileListContent(
 		}
 		return filtered
 	}
+	// Unmerged paths get their own section
+	var conflictedFiles, unstagedFiles []git.FileInfo
+	for _, f := range modifiedFiles {
+		if f.ChangeStatus == "conflicted" {
+			conflictedFiles = append(conflictedFiles, f)
+		} else {
+			unstagedFiles = append(unstagedFiles, f)
+		}
+	}
+
+	filteredConflicted := filterFn(conflictedFiles)
 	filteredStaged := filterFn(stagedFiles)
-	filteredModified := filterFn(modifiedFiles)
+	filteredModified := filterFn(unstagedFiles)
 	filteredUntracked := filterFn(untrackedFiles)
 
 	var coloredContent strings.Builder
 	regionIndex := 0
 	currentLine := 0
 
+	// Conflicted files
+	if len(filteredConflicted) > 0 {
+		coloredContent.WriteString("[red]Conflicts:[white]\n")
+		currentLine++
+		tree := buildFileTree(filteredConflicted)
+		renderFileTree(tree, 1, &coloredContent, fileList,
+			"conflicted", &regionIndex, currentSelection, focusedPane, lineNumberMap, &currentLine, filteredConflicted, collapseState)
+		coloredContent.WriteString("\n")
+		currentLine++
+	}
+
 	// Staged files
 	if len(filteredStaged) > 0 {
 		coloredContent.WriteString("[green]Changes to be committed:[white]\n")
@@ -265,10 +288,14 @@ func BuildFileListContentForCommit(
 
 	tree := buildFileTreeFromFileEntries(commitFiles)
 
-	// Build status map for O(1) lookup
+	// Build status and badge maps for O(1) lookup
 	statusMap := make(map[string]string, len(commitFiles))
+	badgeMap := make(map[string]string)
 	for _, f := range commitFiles {
 		statusMap[f.Path] = f.ChangeStatus
+		if f.Badge != "" {
+			badgeMap[f.Path] = f.Badge
+		}
 	}
 
 	var content strings.Builder
@@ -290,6 +317,7 @@ func BuildFileListContentForCommit(
 		commitFiles,
 		collapseState,
 		statusMap,
+		badgeMap,
 	)
 
 	return content.String()
@@ -307,6 +335,7 @@ type FileListKeyContext struct {
 	contentFlex     *tview.Flex
 	app             *tview.Application
 	mainView        tview.Primitive // reference to the main view
+	mainFlex        *tview.Flex     // main layout, where the fetch/pull/push panel is added
 
 	// State
 	currentSelection  *int
@@ -345,6 +374,10 @@ type FileListKeyContext struct {
 	filterInput  string
 	filterQuery  *string // active filter (empty = no filter)
 
+	// Fetch, pull and push state
+	remoteCommandRunning bool            // a fetch, pull or push runs in the background
+	remotePanel          *tview.TextView // panel of the last run, left open after a failure
+
 	// Callbacks
 	updateFileListView     func()
 	updateSelectedFileDiff func()
@@ -353,8 +386,10 @@ type FileListKeyContext struct {
 	updateGlobalStatus     func(string, string)
 	updateStatusTitle      func()
 	setGlobalStatusText    func(string)
-	onEsc                  func() // if non-nil, called on Esc key
-	openTerminal           func() // if non-nil, opens terminal command input
+	onUpdate               func()            // if non-nil, rebuilds the main view after the repository changed
+	onEsc                  func()            // if non-nil, called on Esc key
+	openTerminal           func()            // if non-nil, opens terminal command input
+	startReword            func(hash string) // if non-nil, edits the message of a commit in the commit area
 }
 
 // applyFileFilter updates the file list selection to match the filter query
@@ -570,19 +605,35 @@ func SetupFileListKeyBindings(ctx *FileListKeyContext) {
 				return nil
 			}
 			// Create Git Log View
-			gitLogView := NewGitLogView(ctx.app, ctx.repoRoot, func() {
+			gitLogView := NewGitLogView(ctx.app, ctx.repoRoot, func(changed bool) {
 				ctx.app.SetRoot(ctx.mainView, true)
 				ctx.app.SetFocus(ctx.fileListView)
-			})
+				if changed && ctx.onUpdate != nil {
+					ctx.onUpdate()
+				}
+			}, ctx.startReword)
 			ctx.app.SetRoot(gitLogView.GetView(), true)
 			return nil
+		case tcell.KeyCtrlR:
+			if ctx.readOnly {
+				return nil
+			}
+			redoLastAction(ctx.repoRoot, func() {
+				ctx.refreshFileList()
+				ctx.updateFileListView()
+				ctx.updateSelectedFileDiff()
+			})
+			return nil
 		case tcell.KeyCtrlA:
 			if ctx.readOnly {
 				return nil
 			}
-			cmd := exec.Command("git", "-c", "core.quotepath=false", "add", "--all")
-			cmd.Dir = ctx.repoRoot
-			if err := cmd.Run(); err != nil {
+			err := journalAction(ctx.repoRoot, "stage all", nil, func() error {
+				cmd := exec.Command("git", "-c", "core.quotepath=false", "add", "--all")
+				cmd.Dir = ctx.repoRoot
+				return cmd.Run()
+			})
+			if err != nil {
 				if ctx.updateGlobalStatus != nil {
 					ctx.updateGlobalStatus("Failed to stage all files", "tomato")
 				}
@@ -710,6 +761,128 @@ func SetupFileListKeyBindings(ctx *FileListKeyContext) {
 					ctx.updateGlobalStatus("Whitespace changes shown", "forestgreen")
 				}
 				return nil
+			case 'D':
+				if ctx.readOnly {
+					return nil
+				}
+				// Show recently discarded changes
+				discardView := NewDiscardView(ctx.app, ctx.repoRoot, discardLog, func(restored bool) {
+					ctx.app.SetRoot(ctx.mainView, true)
+					ctx.app.SetFocus(ctx.fileListView)
+					if restored {
+						ctx.refreshFileList()
+						ctx.updateFileListView()
+						ctx.updateSelectedFileDiff()
+					}
+				})
+				ctx.app.SetRoot(discardView.GetView(), true)
+				return nil
+			case 'b':
+				if ctx.readOnly {
+					return nil
+				}
+				// Show the local branches
+				branchView := NewBranchView(ctx.app, ctx.repoRoot, func(changed bool) {
+					ctx.app.SetRoot(ctx.mainView, true)
+					ctx.app.SetFocus(ctx.fileListView)
+					if changed {
+						ctx.refreshFileList()
+						ctx.updateFileListView()
+						ctx.updateSelectedFileDiff()
+					}
+				})
+				ctx.app.SetRoot(branchView.GetView(), true)
+				return nil
+			case 'h':
+				if ctx.readOnly || *ctx.currentSelection < 0 || *ctx.currentSelection >= len(*ctx.fileList) {
+					return nil
+				}
+				// Show the commits that touched the selected file
+				fileEntry := (*ctx.fileList)[*ctx.currentSelection]
+				if fileEntry.IsDirectory {
+					ctx.updateGlobalStatus("Select a file to show its history", "yellow")
+					return nil
+				}
+				showFileHistory(ctx.app, ctx.repoRoot, fileEntry.Path, ctx.mainView, ctx.fileListView, ctx.updateGlobalStatus)
+				return nil
+			case 'z':
+				if ctx.readOnly {
+					return nil
+				}
+				showStashMenu(ctx)
+				return nil
+			case 'Z':
+				if ctx.readOnly {
+					return nil
+				}
+				// Show the stash list
+				stashView := NewStashView(ctx.app, ctx.repoRoot, func(changed bool) {
+					ctx.app.SetRoot(ctx.mainView, true)
+					ctx.app.SetFocus(ctx.fileListView)
+					if changed {
+						ctx.refreshFileList()
+						ctx.updateFileListView()
+						ctx.updateSelectedFileDiff()
+					}
+				})
+				ctx.app.SetRoot(stashView.GetView(), true)
+				return nil
+			case 'R':
+				if ctx.readOnly {
+					return nil
+				}
+				// Show the reflog to recover earlier states of HEAD and the branches
+				reflogView := NewReflogView(ctx.app, ctx.repoRoot, func(changed bool) {
+					ctx.app.SetRoot(ctx.mainView, true)
+					ctx.app.SetFocus(ctx.fileListView)
+					if changed && ctx.onUpdate != nil {
+						ctx.onUpdate()
+					}
+				})
+				ctx.app.SetRoot(reflogView.GetView(), true)
+				return nil
+			case 'C', 'S', 'X':
+				if ctx.readOnly {
+					return nil
+				}
+				// Continue, skip or abort the merge, rebase, etc. in progress
+				verbs := map[rune]string{'C': "continue", 'S': "skip", 'X': "abort"}
+				continueOperation(ctx, verbs[event.Rune()])
+				return nil
+			case 'f':
+				if ctx.readOnly || ctx.mainFlex == nil {
+					return nil
+				}
+				runRemoteCommand(ctx, "git fetch", "Fetched all remotes", func(progress io.Writer) error {
+					return git.Fetch(ctx.repoRoot, progress)
+				})
+				return nil
+			case 'p':
+				if ctx.readOnly || ctx.mainFlex == nil {
+					return nil
+				}
+				runRemoteCommand(ctx, pullTitle(ctx.repoRoot), "Pulled the upstream", func(progress io.Writer) error {
+					return git.Pull(ctx.repoRoot, progress)
+				})
+				return nil
+			case 'P':
+				if ctx.readOnly || ctx.mainFlex == nil {
+					return nil
+				}
+				runRemoteCommand(ctx, "git push", "Pushed the current branch", func(progress io.Writer) error {
+					return git.Push(ctx.repoRoot, progress)
+				})
+				return nil
+			case 'u':
+				if ctx.readOnly {
+					return nil
+				}
+				undoLastAction(ctx.repoRoot, func() {
+					ctx.refreshFileList()
+					ctx.updateFileListView()
+					ctx.updateSelectedFileDiff()
+				})
+				return nil
 			case 'a': // 'a' to git add/reset the current file/directory
 				if ctx.readOnly {
 					return nil
@@ -719,15 +892,31 @@ func SetupFileListKeyBindings(ctx *FileListKeyContext) {
 					file := fileEntry.Path
 					status := fileEntry.StageStatus
 
+					// Conflicted files are staged only once all markers are resolved
+					if status == "conflicted" && !fileEntry.IsDirectory {
+						if markConflictResolved(file, ctx.repoRoot, ctx.updateGlobalStatus) {
+							ctx.refreshFileList()
+							ctx.updateFileListView()
+							ctx.updateSelectedFileDiff()
+						}
+						return nil
+					}
+
 					// For directories, stage/unstage the entire directory
 					if fileEntry.IsDirectory {
 						file = fileEntry.Path + "/"
 					}
 
+					// Unstaging a rename must also restore its original path
+					resetArgs := []string{"-c", "core.quotepath=false", "reset", "HEAD", "--", file}
+					if fileEntry.OrigPath != "" {
+						resetArgs = append(resetArgs, fileEntry.OrigPath)
+					}
+
 					var cmd *exec.Cmd
 					if status == "staged" {
 						// Unstage the staged file
-						cmd = exec.Command("git", "-c", "core.quotepath=false", "reset", "HEAD", "--", file)
+						cmd = exec.Command("git", resetArgs...)
 						cmd.Dir = ctx.repoRoot
 					} else {
 						// Stage the unstaged/untracked file
@@ -735,23 +924,31 @@ func SetupFileListKeyBindings(ctx *FileListKeyContext) {
 						cmd.Dir = ctx.repoRoot
 					}
 
+					description := "stage " + file
+					if status == "staged" {
+						description = "unstage " + file
+					}
+
 					// Retry to handle git index lock conflicts
-					var err error
-					for retry := 0; retry < 3; retry++ {
-						err = cmd.Run()
-						if err == nil {
-							break
-						}
-						// Wait briefly before retrying
-						time.Sleep(50 * time.Millisecond)
-						// Re-create command (Cmd cannot be reused after execution)
-						if status == "staged" {
-							cmd = exec.Command("git", "-c", "core.quotepath=false", "reset", "HEAD", "--", file)
-						} else {
-							cmd = exec.Command("git", "-c", "core.quotepath=false", "add", file)
+					err := journalAction(ctx.repoRoot, description, nil, func() error {
+						var err error
+						for retry := 0; retry < 3; retry++ {
+							err = cmd.Run()
+							if err == nil {
+								break
+							}
+							// Wait briefly before retrying
+							time.Sleep(50 * time.Millisecond)
+							// Re-create command (Cmd cannot be reused after execution)
+							if status == "staged" {
+								cmd = exec.Command("git", resetArgs...)
+							} else {
+								cmd = exec.Command("git", "-c", "core.quotepath=false", "add", file)
+							}
+							cmd.Dir = ctx.repoRoot
 						}
-						cmd.Dir = ctx.repoRoot
-					}
+						return err
+					})
 					if err != nil {
 						if ctx.updateGlobalStatus != nil {
 							if status == "staged" {
@@ -823,8 +1020,8 @@ func SetupFileListKeyBindings(ctx *FileListKeyContext) {
 				if *ctx.currentSelection >= 0 && *ctx.currentSelection < len(*ctx.fileList) {
 					fileEntry := (*ctx.fileList)[*ctx.currentSelection]
 
-					// Skip directories
-					if fileEntry.IsDirectory {
+					// Skip directories (untracked directories are deleted as a whole)
+					if fileEntry.IsDirectory && fileEntry.StageStatus != "untracked" {
 						if ctx.updateGlobalStatus != nil {
 							ctx.updateGlobalStatus("Cannot discard directory. Select individual files.", "tomato")
 						}
@@ -842,7 +1039,10 @@ func SetupFileListKeyBindings(ctx *FileListKeyContext) {
 					// Set confirmation message
 					var confirmMsg string
 					var buttonLabel string
-					if fileEntry.StageStatus == "untracked" {
+					if fileEntry.IsDirectory {
+						confirmMsg = "Delete " + fileEntry.Path + "/ and all files in it?"
+						buttonLabel = "Delete"
+					} else if fileEntry.StageStatus == "untracked" {
 						confirmMsg = "Delete " + fileEntry.Path + "?"
 						buttonLabel = "Delete"
 					} else {
@@ -860,9 +1060,12 @@ func SetupFileListKeyBindings(ctx *FileListKeyContext) {
 									CurrentFile:   fileEntry.Path,
 									CurrentStatus: fileEntry.StageStatus,
 									RepoRoot:      ctx.repoRoot,
+									DiscardLog:    discardLog,
 								}
 
-								err := commands.CommandD(params)
+								err := journalAction(ctx.repoRoot, "discard "+fileEntry.Path, []string{fileEntry.Path}, func() error {
+									return commands.CommandD(params)
+								})
 								if err != nil {
 									if ctx.updateGlobalStatus != nil {
 										ctx.updateGlobalStatus(err.Error(), "tomato")
@@ -873,9 +1076,9 @@ func SetupFileListKeyBindings(ctx *FileListKeyContext) {
 									ctx.updateSelectedFileDiff()
 									if ctx.updateGlobalStatus != nil {
 										if fileEntry.StageStatus == "untracked" {
-											ctx.updateGlobalStatus("File deleted successfully!", "forestgreen")
+											ctx.updateGlobalStatus("File deleted (D to restore)", "forestgreen")
 										} else {
-											ctx.updateGlobalStatus("Changes discarded successfully!", "forestgreen")
+											ctx.updateGlobalStatus("Changes discarded (D to restore)", "forestgreen")
 										}
 									}
 								}
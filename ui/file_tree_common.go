@@ -16,6 +16,8 @@ type FileEntry struct {
 	Path         string
 	StageStatus  string // "staged", "unstaged", "untracked", "commit" (for git log view)
 	ChangeStatus string // "added", "modified", "deleted", "untracked", "renamed", etc.
+	Badge        string // extra label rendered after the file name (e.g. "[C S -]" in base mode)
+	OrigPath     string // original path of a renamed or copied file
 	IsDirectory  bool
 }
 
@@ -152,7 +154,7 @@ func renderFileTreeForGitFiles(
 	currentLine *int,
 	fileInfos []git.FileInfo,
 	collapseState *DirCollapseState,
-	statusMap map[string]string,
+	infoMap map[string]git.FileInfo,
 ) {
 	// Sort children for consistent ordering
 	var sortedKeys []string
@@ -195,17 +197,20 @@ func renderFileTreeForGitFiles(
 
 		if child.IsFile {
 			// File node
+			info := infoMap[child.FullPath]
 			regionID := fmt.Sprintf("file-%d", *regionIndex)
 			*fileList = append(*fileList, FileEntry{
-				ID:          regionID,
-				Path:        child.FullPath,
-				StageStatus: stageStatus,
+				ID:           regionID,
+				Path:         child.FullPath,
+				StageStatus:  stageStatus,
+				ChangeStatus: info.ChangeStatus,
+				OrigPath:     info.OrigPath,
 			})
 
 			// Add status decoration to filename
 			displayName := child.Name
-			if status, ok := statusMap[child.FullPath]; ok {
-				displayName = formatFileWithStatus(child.Name, status)
+			if info.ChangeStatus != "" {
+				displayName = formatFileWithStatus(child.Name, info.ChangeStatus) + formatFileInfoDetails(info)
 			}
 
 			// Escape tview color tags
@@ -249,7 +254,7 @@ func renderFileTreeForGitFiles(
 			// Only render children if not collapsed
 			if !collapsed {
 				renderFileTreeForGitFiles(child, depth+1, childPrefix, sb, fileList,
-					stageStatus, regionIndex, currentSelection, focusedPane, lineNumberMap, currentLine, fileInfos, collapseState, statusMap)
+					stageStatus, regionIndex, currentSelection, focusedPane, lineNumberMap, currentLine, fileInfos, collapseState, infoMap)
 			}
 		}
 	}
@@ -271,6 +276,7 @@ func renderFileTreeForFileEntries(
 	fileEntries []FileEntry,
 	collapseState *DirCollapseState,
 	statusMap map[string]string,
+	badgeMap map[string]string,
 ) {
 	// Sort children for consistent ordering
 	var sortedKeys []string
@@ -321,6 +327,11 @@ func renderFileTreeForFileEntries(
 				changeStatus = status
 			}
 
+			badge := badgeMap[child.FullPath]
+			if badge != "" {
+				displayName += " " + badge
+			}
+
 			// Escape tview color tags
 			escapedDisplayName := escapeTviewTags(displayName)
 
@@ -331,6 +342,7 @@ func renderFileTreeForFileEntries(
 					Path:         child.FullPath,
 					StageStatus:  stageStatus,
 					ChangeStatus: changeStatus,
+					Badge:        badge,
 					IsDirectory:  false,
 				})
 			}
@@ -375,7 +387,7 @@ func renderFileTreeForFileEntries(
 			// Only render children if not collapsed
 			if !collapsed {
 				renderFileTreeForFileEntries(child, depth+1, childPrefix, sb, fileList,
-					stageStatus, regionIndex, currentSelection, focusedPane, lineNumberMap, currentLine, fileEntries, collapseState, statusMap)
+					stageStatus, regionIndex, currentSelection, focusedPane, lineNumberMap, currentLine, fileEntries, collapseState, statusMap, badgeMap)
 			}
 		}
 	}
@@ -429,11 +441,54 @@ func formatFileWithStatus(filename string, status string) string {
 		return filename + " " + "(-)"
 	case "modified":
 		return filename + " " + "(•)"
+	case "renamed":
+		return filename + " " + "(R)"
+	case "copied":
+		return filename + " " + "(C)"
+	case "type-changed":
+		return filename + " " + "(T)"
+	case "conflicted":
+		return filename + " " + "(!)"
 	default:
 		return filename
 	}
 }
 
+// formatFileInfoDetails describes renames, mode changes, submodules and conflicts
+func formatFileInfoDetails(info git.FileInfo) string {
+	var details strings.Builder
+	if info.OrigPath != "" {
+		details.WriteString(" ← " + info.OrigPath)
+		if info.Similarity > 0 {
+			details.WriteString(fmt.Sprintf(" (%d%%)", info.Similarity))
+		}
+	}
+	if info.Conflict != "" {
+		details.WriteString(" [" + info.Conflict + "]")
+	}
+	if info.HasModeChange() {
+		details.WriteString(" [" + info.OldMode + " → " + info.NewMode + "]")
+	}
+	if sub := info.Submodule; sub != nil {
+		var states []string
+		if sub.CommitChanged {
+			states = append(states, "new commits")
+		}
+		if sub.Modified {
+			states = append(states, "modified")
+		}
+		if sub.Untracked {
+			states = append(states, "untracked")
+		}
+		details.WriteString(" [submodule")
+		if len(states) > 0 {
+			details.WriteString(": " + strings.Join(states, ", "))
+		}
+		details.WriteString("]")
+	}
+	return details.String()
+}
+
 // escapeTviewTags escapes tview color tag characters in text
 func escapeTviewTags(text string) string {
 	return tview.Escape(text)
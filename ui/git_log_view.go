@@ -4,23 +4,19 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"regexp"
 	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
 	"github.com/sukechannnn/giff/util"
 )
 
-// GitLogEntry represents a single git log entry
-type GitLogEntry struct {
-	Hash     string
-	Message  string
-	Author   string
-	Date     string
-	FullLine string // original display line
-}
+// gitLogPageSize is the number of commits loaded at a time
+const gitLogPageSize = 200
+
+const gitLogViewTitle = "Git Log (j/k: navigate, Enter: show commit, m/d: mark/diff with mark, w/i: diff with worktree/index, F/r/s/D: fixup/reword/squash/drop, e: rebase -i, c/v: cherry-pick/revert, x: reset, o: restore file, /: search, f: filter, b: new branch, Esc: exit)"
 
 // GitLogView manages the git log display and navigation
 type GitLogView struct {
@@ -28,27 +24,41 @@ type GitLogView struct {
 	logView      *tview.TextView
 	flex         *tview.Flex
 	repoRoot     string
-	logEntries   []GitLogEntry
+	logEntries   []git.LogEntry
 	currentLine  int
 	showingCommit bool
-	onExit       func()
+	onExit       func(changed bool)
 	scrollOffset int
 	commitFiles  []FileEntry
 	// State for gg command
 	gPressed  *bool
 	lastGTime *time.Time
+	// Filtered, lazily loaded log
+	filter  git.LogFilter
+	hasMore bool       // more commits can be loaded past the last entry
+	graph   []graphRow // graph of logEntries, nil when the filter hides commits in between
+	loadErr error      // error of the last page load
+	// Search state
+	searchQuery string
+	// Commit marked with m, to compare with the selected one
+	marked string
+	// History rewritten from the log, so the main view needs a refresh
+	changed bool
+	// Opens the commit message editor of the main view to reword a commit
+	onReword func(hash string)
 }
 
-// NewGitLogView creates a new git log viewer
-func NewGitLogView(app *tview.Application, repoRoot string, onExit func()) *GitLogView {
+// NewGitLogView creates a new git log viewer. onReword hands a commit over to
+// the commit message editor of the main view (nil disables rewording).
+func NewGitLogView(app *tview.Application, repoRoot string, onExit func(changed bool), onReword func(hash string)) *GitLogView {
 	logView := tview.NewTextView().
 		SetDynamicColors(true).
 		SetWrap(false).
 		SetScrollable(true)
-	logView.SetBorder(true).SetTitle("Git Log (j/k: navigate, Enter: show commit, Esc: exit)")
+	logView.SetBorder(true).SetTitle(gitLogViewTitle)
 	logView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
 
-	flex := tview.NewFlex().
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(logView, 0, 1, true)
 
 	glv := &GitLogView{
@@ -56,10 +66,11 @@ func NewGitLogView(app *tview.Application, repoRoot string, onExit func()) *GitL
 		logView:      logView,
 		flex:         flex,
 		repoRoot:     repoRoot,
-		logEntries:   []GitLogEntry{},
+		logEntries:   []git.LogEntry{},
 		currentLine:  0,
 		showingCommit: false,
 		onExit:       onExit,
+		onReword:     onReword,
 		scrollOffset: 0,
 		commitFiles:  []FileEntry{},
 		gPressed:     new(bool),
@@ -83,85 +94,99 @@ func (glv *GitLogView) GetView() tview.Primitive {
 	return glv.flex
 }
 
-// convertANSIToTviewColors converts ANSI escape codes to tview color tags
-func convertANSIToTviewColors(text string) string {
-	patterns := []struct {
-		pattern     string
-		replacement string
-	}{
-		{`\x1b\[1;31m`, "[red]"},
-		{`\x1b\[31m`, "[red]"},
-		{`\x1b\[1;32m`, "[green]"},
-		{`\x1b\[32m`, "[green]"},
-		{`\x1b\[1;33m`, "[yellow]"},
-		{`\x1b\[33m`, "[yellow]"},
-		{`\x1b\[1;34m`, "[blue]"},
-		{`\x1b\[34m`, "[blue]"},
-		{`\x1b\[1;35m`, "[purple]"},
-		{`\x1b\[35m`, "[purple]"},
-		{`\x1b\[1;36m`, "[cyan]"},
-		{`\x1b\[36m`, "[cyan]"},
-		{`\x1b\[1;37m`, "[white]"},
-		{`\x1b\[37m`, "[white]"},
-		{`\x1b\[1m`, ""},
-		{`\x1b\[m`, "[-]"},
-		{`\x1b\[0m`, "[-]"},
-		{`\x1b\[(0;)?39m`, "[-]"},
-	}
-
-	result := text
-	for _, p := range patterns {
-		re := regexp.MustCompile(p.pattern)
-		result = re.ReplaceAllString(result, p.replacement)
-	}
-
-	re := regexp.MustCompile(`\x1b\[[0-9;]*m`)
-	result = re.ReplaceAllString(result, "")
-
-	return result
-}
-
-// loadGitLog executes git log and parses the output
+// loadGitLog loads the first page of the log with the current filter
 func (glv *GitLogView) loadGitLog() {
-	cmd := exec.Command("git", "log", "--graph", "--pretty=format:%C(yellow)%h%Creset - %s %Cgreen(%cr) %C(bold blue)<%an>%Creset", "--color=always", "-n", "200")
-	cmd.Dir = glv.repoRoot
+	glv.logEntries = []git.LogEntry{}
+	glv.currentLine = 0
+	glv.scrollOffset = 0
+	glv.hasMore = true
+	glv.loadMoreCommits()
+	glv.updateSelection()
+}
 
-	output, err := cmd.Output()
+// loadMoreCommits appends the next page of commits, if any
+func (glv *GitLogView) loadMoreCommits() {
+	if !glv.hasMore {
+		return
+	}
+	entries, err := git.GetLog(glv.repoRoot, glv.filter, len(glv.logEntries), gitLogPageSize)
+	glv.loadErr = err
 	if err != nil {
-		glv.logView.SetText("[red]Failed to load git log: " + err.Error())
+		glv.hasMore = false
 		return
 	}
+	glv.logEntries = append(glv.logEntries, entries...)
+	glv.graph = nil
+	if !glv.filter.LimitsCommits() {
+		glv.graph = buildLogGraph(glv.logEntries)
+	}
+	glv.hasMore = len(entries) == gitLogPageSize
+}
 
-	lines := strings.Split(string(output), "\n")
-	glv.logEntries = []GitLogEntry{}
-
-	hashRegex := regexp.MustCompile(`([a-f0-9]{7,})`)
-
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-
-		cleanLine := convertANSIToTviewColors(line)
+// moveTo moves the selection, loading more commits when it gets near the end
+func (glv *GitLogView) moveTo(line int) {
+	if line >= len(glv.logEntries)-1 {
+		glv.loadMoreCommits()
+	}
+	glv.currentLine = max(0, min(line, len(glv.logEntries)-1))
+	glv.updateSelection()
+}
 
-		var hash string
-		if matches := hashRegex.FindStringSubmatch(line); len(matches) > 0 {
-			hash = matches[0]
+// formatRefs renders the decorations of a commit like git log does
+func formatRefs(refs []string) string {
+	labels := make([]string, len(refs))
+	for i, ref := range refs {
+		color := util.LogRefBranchColor
+		switch {
+		case strings.HasPrefix(ref, "HEAD"):
+			color = util.LogRefHeadColor
+		case strings.HasPrefix(ref, "tag: "):
+			color = util.LogRefTagColor
+		case strings.Contains(ref, "/"):
+			color = util.LogRefRemoteColor
 		}
+		labels[i] = "[" + string(color) + "]" + tview.Escape(ref) + "[-]"
+	}
+	delimiter := "[" + string(util.LogRefDelimiterColor) + "]"
+	return delimiter + "([-]" + strings.Join(labels, delimiter+", [-]") + delimiter + ")[-]"
+}
 
-		entry := GitLogEntry{
-			Hash:     hash,
-			FullLine: cleanLine,
+// formatLogEntry renders a commit as one line of the log after its graph.
+// Commits outside the ancestry of the selected commit are dimmed.
+func formatLogEntry(entry git.LogEntry, graph string, selected, dimmed bool) string {
+	if graph != "" {
+		graph += " "
+	}
+	if selected {
+		refs := ""
+		if len(entry.Refs) > 0 {
+			refs = " (" + strings.Join(entry.Refs, ", ") + ")"
 		}
-		glv.logEntries = append(glv.logEntries, entry)
+		return "[white:blue]" + graph + "[white]" + tview.Escape(shortHash(entry.Hash)+refs+" - "+entry.Subject+" ("+entry.Date+") <"+entry.Author+">") + "[-:-]"
 	}
-
-	glv.updateSelection()
+	refs := ""
+	if len(entry.Refs) > 0 {
+		refs = " " + formatRefs(entry.Refs)
+	}
+	if dimmed {
+		return graph + "[" + string(util.PlaceholderColor) + "]" + shortHash(entry.Hash) + "[-]" + refs + "[" + string(util.PlaceholderColor) + "] - " +
+			tview.Escape(entry.Subject+" ("+entry.Date+") <"+entry.Author+">") + "[-]"
+	}
+	return graph + "[" + string(util.LogHashColor) + "]" + shortHash(entry.Hash) + "[-]" + refs + " - " + tview.Escape(entry.Subject) +
+		" [" + string(util.LogDateColor) + "](" + entry.Date + ")[-] [" + string(util.LogAuthorColor) + "]<" + tview.Escape(entry.Author) + ">[-]"
 }
 
 // updateSelection updates the visual selection in the log view
 func (glv *GitLogView) updateSelection() {
 	if len(glv.logEntries) == 0 {
+		switch {
+		case glv.loadErr != nil:
+			glv.logView.SetText("[red]Failed to load git log: " + tview.Escape(glv.loadErr.Error()))
+		case !glv.filter.IsEmpty():
+			glv.logView.SetText("No commits match the filter")
+		default:
+			glv.logView.SetText("No commits")
+		}
 		return
 	}
 
@@ -197,17 +222,27 @@ func (glv *GitLogView) updateSelection() {
 		endLine = glv.scrollOffset + min(len(glv.logEntries)-glv.scrollOffset, 30)
 	}
 
+	// Highlight the ancestry of the selected commit along the graph
+	var ancestry map[string]bool
+	if glv.graph != nil {
+		ancestry = logAncestry(glv.logEntries, glv.logEntries[glv.currentLine].Hash)
+	}
+
 	var content strings.Builder
 	for i := glv.scrollOffset; i < endLine; i++ {
 		if i >= len(glv.logEntries) {
 			break
 		}
 		entry := glv.logEntries[i]
-		if i == glv.currentLine {
-			content.WriteString("[white:blue]" + entry.FullLine + "[-:-]\n")
-		} else {
-			content.WriteString(entry.FullLine + "\n")
+		graph := ""
+		ancestor := ancestry == nil || ancestry[entry.Hash]
+		if i < len(glv.graph) {
+			graph = glv.graph[i].render(ancestor)
+		}
+		if entry.Hash == glv.marked {
+			graph += " [red::b]◆[-::-]"
 		}
+		content.WriteString(formatLogEntry(entry, graph, i == glv.currentLine, !ancestor) + "\n")
 	}
 
 	glv.logView.SetText(content.String())
@@ -222,6 +257,11 @@ func min(a, b int) int {
 
 // loadCommitFiles loads the list of files changed in a commit
 func (glv *GitLogView) loadCommitFiles(commitHash string) {
+	glv.commitFiles = getCommitFiles(glv.repoRoot, commitHash)
+}
+
+// getCommitFiles returns the files changed in a commit
+func getCommitFiles(repoRoot, commitHash string) []FileEntry {
 	cmd := exec.Command(
 		"git",
 		"-c", "core.quotepath=false",
@@ -230,16 +270,15 @@ func (glv *GitLogView) loadCommitFiles(commitHash string) {
 		"--format=",
 		commitHash,
 	)
-	cmd.Dir = glv.repoRoot
+	cmd.Dir = repoRoot
 
 	output, err := cmd.Output()
 	if err != nil {
-		glv.commitFiles = []FileEntry{}
-		return
+		return []FileEntry{}
 	}
 
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	glv.commitFiles = []FileEntry{}
+	files := []FileEntry{}
 
 	for _, line := range lines {
 		if line == "" {
@@ -262,8 +301,9 @@ func (glv *GitLogView) loadCommitFiles(commitHash string) {
 			StageStatus:  "commit",
 			ChangeStatus: normalizeCommitStatus(rawStatus),
 		}
-		glv.commitFiles = append(glv.commitFiles, fileInfo)
+		files = append(files, fileInfo)
 	}
+	return files
 }
 
 // normalizeCommitStatus converts git show status codes to descriptive labels
@@ -301,347 +341,197 @@ func (glv *GitLogView) showCommitDetails() {
 		return
 	}
 
+	// git show would print a combined diff; let the user pick what to compare
+	if len(entry.Parents) > 1 {
+		glv.chooseMergeDiff(entry)
+		return
+	}
+
 	commitHash := entry.Hash
 	glv.loadCommitFiles(commitHash)
 
-	// Create UI components (same pattern as root_editor.go)
-	fileListView := tview.NewTextView().
-		SetDynamicColors(true).
-		SetRegions(true).
-		SetWrap(false)
-	fileListView.SetBorder(true).SetTitle("Changed Files")
-	fileListView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+	layout := newCommitDetailLayout(glv.app, glv.repoRoot, commitHash, glv.commitFiles, glv.flex, glv.backToLog)
 
-	diffView := tview.NewTextView().
-		SetDynamicColors(true).
-		SetRegions(true).
-		SetWrap(false)
-	diffView.SetBorder(true).SetTitle("File Diff")
-	diffView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
-	diffView.SetBorderStyle(tcell.StyleDefault)
+	// Switch display
+	glv.showLayout(layout)
+}
 
-	unifiedViewFlex := tview.NewFlex().
-		AddItem(diffView, 0, 1, false)
-	unifiedViewFlex.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+// showLayout replaces the log with a diff layout until Esc
+func (glv *GitLogView) showLayout(layout *ReadOnlyDiffLayout) {
+	glv.flex.Clear()
+	glv.flex.AddItem(layout.View, 0, 1, true)
+	glv.showingCommit = true
+	glv.app.SetFocus(layout.FileListView)
+}
 
-	beforeView := tview.NewTextView().
-		SetDynamicColors(true).
-		SetRegions(true).
-		SetWrap(false)
-	beforeView.SetBorder(true).SetTitle("Before")
-	beforeView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+// selectedEntry returns the commit under the cursor
+func (glv *GitLogView) selectedEntry() (git.LogEntry, bool) {
+	if glv.currentLine >= len(glv.logEntries) || glv.logEntries[glv.currentLine].Hash == "" {
+		return git.LogEntry{}, false
+	}
+	return glv.logEntries[glv.currentLine], true
+}
 
-	afterView := tview.NewTextView().
-		SetDynamicColors(true).
-		SetRegions(true).
-		SetWrap(false)
-	afterView.SetBorder(true).SetTitle("After")
-	afterView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
-
-	splitViewFlex := tview.NewFlex().
-		AddItem(beforeView, 0, 1, false).
-		AddItem(afterView, 0, 1, false)
-	splitViewFlex.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
-
-	// Status bar
-	statusView := tview.NewTextView().
-		SetDynamicColors(true).
-		SetTextAlign(tview.AlignLeft).
-		SetWrap(false)
-	statusView.SetBorder(true)
-	statusView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
-
-	commitStatusMessage := "j/k:move  /:search  e:fold  s:split  V:select  C-y:copy  Esc:back  q:quit"
-	statusView.SetText(commitStatusMessage)
-
-	contentFlex := tview.NewFlex()
-	contentFlex.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
-	contentFlex.
-		AddItem(fileListView, 0, FileListFlexRatio, true).
-		AddItem(unifiedViewFlex, 0, DiffViewFlexRatio, false)
-
-	// Vertical layout with status bar + content
-	commitMainFlex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(statusView, 3, 0, false).
-		AddItem(contentFlex, 0, 1, true)
-	commitMainFlex.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
-
-	// State variables
-	var fileList []FileEntry
-	lineNumberMap := make(map[int]int)
-	dirCollapseState := NewDirCollapseState()
-	var currentSelection int
-	var cursorY int
-	var currentFile string
-	var currentStatus string
-	var currentDiffText string
-	var isSplitView bool
-	var leftPaneFocused = true
-	var isSelecting bool
-	var selectStart = -1
-	var selectEnd = -1
-	var preserveScrollRow = -1
-	var ignoreWhitespace bool
-	var gPressed bool
-	var lastGTime time.Time
-	var searchQuery string
-	var searchMatches []int
-	var searchMatchIndex = -1
-	var isSearchMode bool
-	var searchInput string
-	var searchCursorYBeforeSearch int
-	foldState := NewFoldState()
-
-	// Diff retrieval callback (git show)
-	updateDiffText := func(filePath, status, repo string, out *string, _ bool) {
-		cmd := exec.Command("git", "show", "--format=", commitHash, "--", filePath)
-		cmd.Dir = glv.repoRoot
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			*out = ""
-			return
-		}
-		*out = strings.TrimLeft(string(output), "\n")
-	}
-
-	// Build file list
-	buildFileListContent := func(focusedPane bool) string {
-		return BuildFileListContentForCommit(
-			glv.commitFiles,
-			currentSelection,
-			focusedPane,
-			&fileList,
-			lineNumberMap,
-			dirCollapseState,
-		)
-	}
-
-	updateFileListView := func() {
-		currentRow, currentCol := fileListView.GetScrollOffset()
-		shouldPreserveScroll := preserveScrollRow >= 0
-		if shouldPreserveScroll {
-			currentRow = preserveScrollRow
-			preserveScrollRow = -1
-		}
+// showRangeDiff shows the files that differ in a revision range
+func (glv *GitLogView) showRangeDiff(revisionRange git.RevisionRange) {
+	layout, count, err := newRevisionDiffLayout(glv.app, glv.repoRoot, revisionRange, readOnlyDiffKeyMessage, glv.flex, glv.backToLog)
+	if err != nil {
+		glv.flashTitle(err.Error(), "tomato")
+		return
+	}
+	if count == 0 {
+		glv.flashTitle("No differences in "+displayRevisionRange(revisionRange), "yellow")
+		return
+	}
+	glv.showLayout(layout)
+}
 
-		fileListView.Clear()
-		fileListView.SetText(buildFileListContent(leftPaneFocused))
-
-		if shouldPreserveScroll {
-			fileListView.ScrollTo(currentRow, currentCol)
-		} else {
-			if actualLine, exists := lineNumberMap[currentSelection]; exists {
-				_, _, _, height := fileListView.GetInnerRect()
-				if actualLine >= currentRow+height-1 {
-					fileListView.ScrollTo(actualLine-height+2, currentCol)
-				} else if actualLine < currentRow {
-					if currentSelection == 0 {
-						fileListView.ScrollTo(0, currentCol)
-					} else {
-						fileListView.ScrollTo(actualLine, currentCol)
-					}
-				} else {
-					fileListView.ScrollTo(currentRow, currentCol)
-				}
-			}
-		}
+// toggleMark marks the selected commit for comparison, or clears the mark
+func (glv *GitLogView) toggleMark() {
+	entry, ok := glv.selectedEntry()
+	if !ok {
+		return
+	}
+	if glv.marked == entry.Hash {
+		glv.marked = ""
+		glv.updateSelection()
+		glv.flashTitle("Mark cleared", "yellow")
+		return
 	}
+	glv.marked = entry.Hash
+	glv.updateSelection()
+	glv.flashTitle("Marked "+shortHash(entry.Hash)+" (d on another commit: diff)", "forestgreen")
+}
 
-	updateSelectedFileDiff := func() {
-		if len(fileList) == 0 {
-			currentDiffText = ""
-			currentFile = ""
-			currentStatus = ""
-			if isSplitView {
-				beforeView.SetText("")
-				afterView.SetText("No files changed")
-			} else {
-				diffView.SetText("No files changed")
-			}
-			return
-		}
+// diffWithMarked compares the marked commit with the selected one, older to newer
+func (glv *GitLogView) diffWithMarked() {
+	entry, ok := glv.selectedEntry()
+	if !ok {
+		return
+	}
+	if glv.marked == "" {
+		glv.flashTitle("Mark a commit with m first", "yellow")
+		return
+	}
+	if glv.marked == entry.Hash {
+		glv.flashTitle("Select another commit to compare with the marked one", "yellow")
+		return
+	}
 
-		if currentSelection < 0 {
-			currentSelection = 0
-		} else if currentSelection >= len(fileList) {
-			currentSelection = len(fileList) - 1
+	// The log lists newer commits first
+	from, to := glv.marked, entry.Hash
+	for i, e := range glv.logEntries {
+		if e.Hash == glv.marked && i < glv.currentLine {
+			from, to = entry.Hash, glv.marked
+			break
 		}
+	}
+	glv.showRangeDiff(git.RevisionRange{From: from, To: to})
+}
 
-		fileEntry := fileList[currentSelection]
+// diffWithWorkingTree compares the selected commit with the working tree, or
+// with the index
+func (glv *GitLogView) diffWithWorkingTree(index bool) {
+	entry, ok := glv.selectedEntry()
+	if !ok {
+		return
+	}
+	glv.showRangeDiff(git.RevisionRange{From: entry.Hash, Index: index})
+}
 
-		if fileEntry.IsDirectory {
-			currentDiffText = ""
-			currentFile = ""
-			currentStatus = ""
-			dirText := "dir: " + fileEntry.Path + "/"
-			if isSplitView {
-				beforeView.SetText("")
-				afterView.SetText(dirText)
-			} else {
-				diffView.SetText(dirText)
+// chooseMergeDiff asks which parent to diff a merge commit against, or for the combined diff
+func (glv *GitLogView) chooseMergeDiff(entry git.LogEntry) {
+	buttons := make([]string, 0, len(entry.Parents)+2)
+	for i, parent := range entry.Parents {
+		buttons = append(buttons, fmt.Sprintf("Parent %d (%s)", i+1, shortHash(parent)))
+	}
+	buttons = append(buttons, "Combined", "Cancel")
+
+	modal := tview.NewModal().
+		SetText("Diff merge " + shortHash(entry.Hash) + " against").
+		AddButtons(buttons).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			glv.app.SetRoot(glv.flex, true)
+			glv.app.SetFocus(glv.logView)
+			switch {
+			case buttonIndex >= 0 && buttonIndex < len(entry.Parents):
+				glv.showRangeDiff(git.RevisionRange{From: entry.Parents[buttonIndex], To: entry.Hash})
+			case buttonLabel == "Combined":
+				glv.showCombinedDiff(entry)
 			}
-			return
-		}
-
-		currentFile = fileEntry.Path
-		currentStatus = fileEntry.StageStatus
-
-		cursorY = 0
-		isSelecting = false
-		selectStart = -1
-		selectEnd = -1
+		})
 
-		updateDiffText(currentFile, currentStatus, glv.repoRoot, &currentDiffText, ignoreWhitespace)
+	pages := tview.NewPages().
+		AddPage("main", glv.flex, true, true).
+		AddPage("modal", modal, true, true)
+	glv.app.SetRoot(pages, true)
+}
 
-		if isSplitView {
-			updateSplitViewWithoutCursor(beforeView, afterView, currentDiffText, currentFile)
-		} else {
-			updateDiffViewWithoutCursor(diffView, currentDiffText, foldState, currentFile, glv.repoRoot)
-		}
+// showCombinedDiff shows the files of a merge commit that differ from every parent
+func (glv *GitLogView) showCombinedDiff(entry git.LogEntry) {
+	files, err := git.GetMergeChangedFiles(entry.Hash, glv.repoRoot)
+	if err != nil {
+		glv.flashTitle(err.Error(), "tomato")
+		return
 	}
-
-	updateFileListView()
-
-	// If initial selection is a directory, select the first file
-	if currentSelection < len(fileList) && fileList[currentSelection].IsDirectory {
-		for i, entry := range fileList {
-			if !entry.IsDirectory {
-				currentSelection = i
-				updateFileListView()
-				break
-			}
-		}
+	if len(files) == 0 {
+		glv.flashTitle("Combined diff of "+shortHash(entry.Hash)+" is empty (no file differs from every parent)", "yellow")
+		return
+	}
+	entries := make([]FileEntry, 0, len(files))
+	for _, f := range files {
+		entries = append(entries, FileEntry{
+			Path:         f.Path,
+			StageStatus:  "commit",
+			ChangeStatus: f.ChangeStatus,
+		})
 	}
 
-	updateSelectedFileDiff()
-
-	// Build DiffViewContext
-	diffViewContext := &DiffViewContext{
-		diffView:        diffView,
-		fileListView:    fileListView,
-		beforeView:      beforeView,
-		afterView:       afterView,
-		splitViewFlex:   splitViewFlex,
-		unifiedViewFlex: unifiedViewFlex,
-		contentFlex:     contentFlex,
-		app:             glv.app,
-
-		cursorY:               &cursorY,
-		selectStart:           &selectStart,
-		selectEnd:             &selectEnd,
-		isSelecting:           &isSelecting,
-		isSplitView:           &isSplitView,
-		leftPaneFocused:       &leftPaneFocused,
-		currentDiffText:       &currentDiffText,
-		currentFile:           &currentFile,
-		currentStatus:         &currentStatus,
-		savedTargetFile:       new(string),
-		preferUnstagedSection: new(bool),
-		currentSelection:      &currentSelection,
-		fileList:              &fileList,
-		preserveScrollRow:     &preserveScrollRow,
-		ignoreWhitespace:      &ignoreWhitespace,
-
-		repoRoot:  glv.repoRoot,
-		patchPath: "",
-
-		gPressed:  &gPressed,
-		lastGTime: &lastGTime,
-
-		viewUpdater: &UnifiedViewUpdater{
-			diffView:    diffView,
-			foldState:   foldState,
-			filePath:    &currentFile,
-			repoRoot:    glv.repoRoot,
-			searchQuery: &searchQuery,
+	layout := NewReadOnlyDiffLayout(ReadOnlyDiffLayoutParams{
+		App:           glv.app,
+		RepoRoot:      glv.repoRoot,
+		Files:         entries,
+		FileListTitle: "Combined diff of " + shortHash(entry.Hash),
+		DiffViewTitle: "parents → " + shortHash(entry.Hash),
+		StatusMessage: readOnlyDiffKeyMessage,
+		MainView:      glv.flex,
+		LoadDiff: func(filePath string, ignoreWhitespace bool) string {
+			diffText, err := git.GetCombinedDiffWithOptions(filePath, glv.repoRoot, entry.Hash, ignoreWhitespace)
+			if err != nil {
+				return ""
+			}
+			return diffText
 		},
+		OnEsc: glv.backToLog,
+	})
+	glv.showLayout(layout)
+}
 
-		foldState: foldState,
-
-		searchQuery:               &searchQuery,
-		searchMatches:             &searchMatches,
-		searchMatchIndex:          &searchMatchIndex,
-		isSearchMode:              &isSearchMode,
-		searchInput:               &searchInput,
-		searchCursorYBeforeSearch: &searchCursorYBeforeSearch,
-
-		readOnly: true,
-
-		updateFileListView: updateFileListView,
-		updateGlobalStatus: func(message string, color string) {
-			statusView.SetText(fmt.Sprintf("[%s]%s[-]", color, message))
-			go func() {
-				time.Sleep(5 * time.Second)
-				statusView.SetText(commitStatusMessage)
-			}()
-		},
-		setGlobalStatusText: func(text string) {
-			statusView.SetText(text)
-		},
-		refreshFileList:       func() {},
-		onUpdate:              nil,
-		updateCurrentDiffText: updateDiffText,
-		updateStatusTitle:     func() {},
-		onEsc:                 glv.backToLog,
-	}
-	SetupDiffViewKeyBindings(diffViewContext)
-
-	// Build FileListKeyContext
-	fileListKeyContext := &FileListKeyContext{
-		fileListView:    fileListView,
-		diffView:        diffView,
-		beforeView:      beforeView,
-		afterView:       afterView,
-		splitViewFlex:   splitViewFlex,
-		unifiedViewFlex: unifiedViewFlex,
-		contentFlex:     contentFlex,
-		app:             glv.app,
-		mainView:        glv.flex,
-
-		currentSelection:  &currentSelection,
-		cursorY:           &cursorY,
-		isSelecting:       &isSelecting,
-		selectStart:       &selectStart,
-		selectEnd:         &selectEnd,
-		isSplitView:       &isSplitView,
-		leftPaneFocused:   &leftPaneFocused,
-		currentFile:       &currentFile,
-		currentStatus:     &currentStatus,
-		currentDiffText:   &currentDiffText,
-		preserveScrollRow: &preserveScrollRow,
-		ignoreWhitespace:  &ignoreWhitespace,
-
-		fileList:         &fileList,
-		dirCollapseState: dirCollapseState,
-		repoRoot:         glv.repoRoot,
-		diffViewContext:  diffViewContext,
-
-		readOnly: true,
-
-		updateFileListView:     updateFileListView,
-		updateSelectedFileDiff: updateSelectedFileDiff,
-		refreshFileList:        func() {},
-		updateCurrentDiffText:  updateDiffText,
-		updateGlobalStatus: func(message string, color string) {
-			statusView.SetText(fmt.Sprintf("[%s]%s[-]", color, message))
-			go func() {
-				time.Sleep(5 * time.Second)
-				statusView.SetText(commitStatusMessage)
-			}()
-		},
-		updateStatusTitle: func() {},
-		setGlobalStatusText: func(text string) {
-			statusView.SetText(text)
+// newCommitDetailLayout builds the file tree + diff layout of a single commit
+func newCommitDetailLayout(app *tview.Application, repoRoot, commitHash string, files []FileEntry, mainView tview.Primitive, onEsc func()) *ReadOnlyDiffLayout {
+	return NewReadOnlyDiffLayout(ReadOnlyDiffLayoutParams{
+		App:           app,
+		RepoRoot:      repoRoot,
+		Files:         files,
+		StatusMessage: readOnlyDiffKeyMessage,
+		MainView:      mainView,
+		// Diff retrieval callback (git show)
+		LoadDiff: func(filePath string, ignoreWhitespace bool) string {
+			args := []string{"-c", "core.quotepath=false", "show", "--format="}
+			if ignoreWhitespace {
+				args = append(args, "-w")
+			}
+			args = append(args, commitHash, "--", filePath)
+			cmd := exec.Command("git", args...)
+			cmd.Dir = repoRoot
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				return ""
+			}
+			return strings.TrimLeft(string(output), "\n")
 		},
-		onEsc: glv.backToLog,
-	}
-	SetupFileListKeyBindings(fileListKeyContext)
-
-	// Switch display
-	glv.flex.Clear()
-	glv.flex.AddItem(commitMainFlex, 0, 1, true)
-	glv.showingCommit = true
-	glv.app.SetFocus(fileListView)
+		OnEsc: onEsc,
+	})
 }
 
 // backToLog returns to the log view from commit details
@@ -657,8 +547,20 @@ func (glv *GitLogView) setupLogKeyBindings() {
 	glv.logView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
 		case tcell.KeyEsc:
+			// Clear the search, then the filter, before leaving
+			if glv.searchQuery != "" {
+				glv.searchQuery = ""
+				glv.logView.SetTitle(glv.title())
+				return nil
+			}
+			if !glv.filter.IsEmpty() {
+				glv.filter = git.LogFilter{}
+				glv.logView.SetTitle(glv.title())
+				glv.loadGitLog()
+				return nil
+			}
 			if glv.onExit != nil {
-				glv.onExit()
+				glv.onExit(glv.changed)
 			}
 			return nil
 		case tcell.KeyEnter:
@@ -670,17 +572,65 @@ func (glv *GitLogView) setupLogKeyBindings() {
 		case 'q':
 			glv.quitApplication()
 			return nil
+		case 'b':
+			glv.promptCreateBranch()
+			return nil
+		case '/':
+			glv.promptSearch()
+			return nil
+		case 'n':
+			glv.searchNext(1)
+			return nil
+		case 'N':
+			glv.searchNext(-1)
+			return nil
+		case 'f':
+			glv.showFilterForm()
+			return nil
+		case 'm':
+			glv.toggleMark()
+			return nil
+		case 'd':
+			glv.diffWithMarked()
+			return nil
+		case 'w':
+			glv.diffWithWorkingTree(false)
+			return nil
+		case 'i':
+			glv.diffWithWorkingTree(true)
+			return nil
+		case 'F':
+			glv.fixupSelected()
+			return nil
+		case 'r':
+			glv.rewordSelected()
+			return nil
+		case 's':
+			glv.rewrite("Squashed", "squashing", git.SquashIntoParent)
+			return nil
+		case 'D':
+			glv.confirmDrop()
+			return nil
+		case 'e':
+			glv.showRebaseEditor()
+			return nil
+		case 'c':
+			glv.applySelected("Cherry-picked", "cherry-pick", git.CherryPick)
+			return nil
+		case 'v':
+			glv.applySelected("Reverted", "revert", git.Revert)
+			return nil
+		case 'x':
+			glv.chooseReset()
+			return nil
+		case 'o':
+			glv.promptRestoreFile()
+			return nil
 		case 'j':
-			if glv.currentLine < len(glv.logEntries)-1 {
-				glv.currentLine++
-				glv.updateSelection()
-			}
+			glv.moveTo(glv.currentLine + 1)
 			return nil
 		case 'k':
-			if glv.currentLine > 0 {
-				glv.currentLine--
-				glv.updateSelection()
-			}
+			glv.moveTo(glv.currentLine - 1)
 			return nil
 		case 'g':
 			now := time.Now()
@@ -696,8 +646,7 @@ func (glv *GitLogView) setupLogKeyBindings() {
 			return nil
 		case 'G':
 			if len(glv.logEntries) > 0 {
-				glv.currentLine = len(glv.logEntries) - 1
-				glv.updateSelection()
+				glv.moveTo(len(glv.logEntries) - 1)
 			}
 			return nil
 		}
@@ -706,6 +655,317 @@ func (glv *GitLogView) setupLogKeyBindings() {
 	})
 }
 
+// title returns the log title with the active filter
+func (glv *GitLogView) title() string {
+	if glv.filter.IsEmpty() {
+		return gitLogViewTitle
+	}
+	return tview.Escape("Git Log [" + glv.filter.String() + "] (f: edit filter, Esc: clear filter)")
+}
+
+// flashTitle shows a message in the title and the key help again after a while.
+// Only the first line of a multi-line git message fits in the title.
+func (glv *GitLogView) flashTitle(message, color string) {
+	message, _, _ = strings.Cut(message, "\n")
+	glv.logView.SetTitle("[" + color + "]" + tview.Escape(message) + "[-]")
+	go func() {
+		time.Sleep(5 * time.Second)
+		glv.app.QueueUpdateDraw(func() {
+			glv.logView.SetTitle(glv.title())
+		})
+	}()
+}
+
+// rewrite runs a history-rewriting action on the selected commit
+func (glv *GitLogView) rewrite(done, doing string, action func(repoRoot, commit string) error) {
+	entry, ok := glv.selectedEntry()
+	if !ok {
+		return
+	}
+	glv.runAction(done+" "+shortHash(entry.Hash),
+		"Rebase stopped on conflicts while "+doing+" "+shortHash(entry.Hash)+": resolve them, then press C to continue",
+		func() error { return action(glv.repoRoot, entry.Hash) })
+}
+
+// operationStopped reports whether a rebase, cherry-pick or revert waits to be
+// continued or aborted
+func (glv *GitLogView) operationStopped() bool {
+	op := git.CurrentOperation(glv.repoRoot)
+	return op != nil && (op.Name == "rebase" || op.Name == "cherry-pick" || op.Name == "revert")
+}
+
+// runAction runs an action that moves HEAD or rewrites history and reloads
+// the log. A rebase, cherry-pick or revert that the action left stopped (on
+// conflicts, or at an edit) is handed over to the main view with the stopped
+// message.
+func (glv *GitLogView) runAction(done, stopped string, action func() error) {
+	wasStopped := glv.operationStopped()
+	err := action()
+	if !wasStopped && glv.operationStopped() {
+		glv.onExit(true)
+		updateGlobalStatus(stopped, "tomato")
+		return
+	}
+	if err != nil {
+		glv.flashTitle(err.Error(), "tomato")
+		return
+	}
+
+	glv.changed = true
+	glv.marked = ""
+	line := glv.currentLine
+	glv.loadGitLog()
+	glv.moveTo(line)
+	glv.flashTitle(done, "forestgreen")
+}
+
+// applySelected cherry-picks or reverts the selected commit onto HEAD
+func (glv *GitLogView) applySelected(done, command string, action func(repoRoot, commit string) error) {
+	entry, ok := glv.selectedEntry()
+	if !ok {
+		return
+	}
+	glv.runAction(done+" "+shortHash(entry.Hash),
+		"The "+command+" of "+shortHash(entry.Hash)+" stopped on conflicts: resolve them, then press C to continue",
+		func() error { return action(glv.repoRoot, entry.Hash) })
+}
+
+// chooseReset asks how to reset HEAD to the selected commit
+func (glv *GitLogView) chooseReset() {
+	entry, ok := glv.selectedEntry()
+	if !ok {
+		return
+	}
+	showResetMenu(glv.app, glv.flex, glv.logView, entry.Hash, entry.Subject, func(mode git.ResetMode) {
+		glv.runAction("Reset ("+mode.String()+") to "+shortHash(entry.Hash), "", func() error {
+			return resetTo(glv.repoRoot, entry.Hash, mode)
+		})
+	})
+}
+
+// promptRestoreFile asks for a path and restores it from the selected commit
+// into the working tree, starting from the first file the commit changed
+func (glv *GitLogView) promptRestoreFile() {
+	entry, ok := glv.selectedEntry()
+	if !ok {
+		return
+	}
+	initial := ""
+	if files := getCommitFiles(glv.repoRoot, entry.Hash); len(files) > 0 {
+		initial = files[0].Path
+	}
+	showInputPrompt(glv.app, glv.flex, "Restore from "+shortHash(entry.Hash)+": ", initial, glv.logView, func(path string) {
+		err := journalAction(glv.repoRoot, "restore "+path+" from "+shortHash(entry.Hash), []string{path}, func() error {
+			return git.RestoreFileFromCommit(glv.repoRoot, entry.Hash, path)
+		})
+		if err != nil {
+			glv.flashTitle(err.Error(), "tomato")
+			return
+		}
+		glv.changed = true
+		glv.flashTitle("Restored "+path+" from "+shortHash(entry.Hash), "forestgreen")
+	})
+}
+
+// showRebaseEditor opens the todo list editor for an interactive rebase from
+// the selected commit to HEAD
+func (glv *GitLogView) showRebaseEditor() {
+	entry, ok := glv.selectedEntry()
+	if !ok {
+		return
+	}
+	backToLog := func() {
+		glv.app.SetRoot(glv.flex, true)
+		glv.app.SetFocus(glv.logView)
+	}
+	rebaseView, err := NewRebaseView(glv.app, glv.repoRoot, entry.Hash, func(items []git.RebaseTodoItem) {
+		backToLog()
+		glv.runAction(fmt.Sprintf("Rebased %d commits", len(items)),
+			"Rebase stopped: amend the commit or resolve the conflicts, then press C to continue",
+			func() error {
+				// Messages of reworded and squashed commits are written in the editor
+				if !git.RebaseTodoNeedsEditor(items) {
+					return git.RunRebaseTodo(glv.repoRoot, entry.Hash, items, false)
+				}
+				var err error
+				glv.app.Suspend(func() {
+					err = git.RunRebaseTodo(glv.repoRoot, entry.Hash, items, true)
+				})
+				return err
+			})
+	}, backToLog)
+	if err != nil {
+		glv.flashTitle(err.Error(), "tomato")
+		return
+	}
+	glv.app.SetRoot(rebaseView.GetView(), true)
+}
+
+// fixupSelected folds the staged changes into the selected commit
+func (glv *GitLogView) fixupSelected() {
+	if !git.HasStagedChanges(glv.repoRoot) {
+		glv.flashTitle("No changes are staged for a fixup", "yellow")
+		return
+	}
+	glv.rewrite("Fixed up", "fixing up", git.FixupCommit)
+}
+
+// rewordSelected leaves the log to edit the message of the selected commit in
+// the commit message editor
+func (glv *GitLogView) rewordSelected() {
+	entry, ok := glv.selectedEntry()
+	if !ok || glv.onReword == nil {
+		return
+	}
+	if err := git.CheckRewritable(glv.repoRoot, entry.Hash); err != nil {
+		glv.flashTitle(err.Error(), "tomato")
+		return
+	}
+	// Exit without a rebuild: the commit area belongs to the current main
+	// view, which refreshes its file list itself
+	glv.onExit(false)
+	glv.onReword(entry.Hash)
+}
+
+// confirmDrop asks for confirmation before dropping the selected commit
+func (glv *GitLogView) confirmDrop() {
+	entry, ok := glv.selectedEntry()
+	if !ok {
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Drop %s (%s) from the branch?", shortHash(entry.Hash), tview.Escape(entry.Subject))).
+		AddButtons([]string{"Drop", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			glv.app.SetRoot(glv.flex, true)
+			glv.app.SetFocus(glv.logView)
+			if buttonLabel == "Drop" {
+				glv.rewrite("Dropped", "dropping", git.DropCommit)
+			}
+		})
+
+	pages := tview.NewPages().
+		AddPage("main", glv.flex, true, true).
+		AddPage("modal", modal, true, true)
+	glv.app.SetRoot(pages, true)
+}
+
+// promptCreateBranch asks for a name and creates a branch at the selected commit
+func (glv *GitLogView) promptCreateBranch() {
+	if glv.currentLine >= len(glv.logEntries) || glv.logEntries[glv.currentLine].Hash == "" {
+		return
+	}
+	hash := glv.logEntries[glv.currentLine].Hash
+	showInputPrompt(glv.app, glv.flex, "New branch at "+shortHash(hash)+": ", "", glv.logView, func(name string) {
+		if err := git.CreateBranch(glv.repoRoot, name, hash); err != nil {
+			glv.flashTitle(err.Error(), "tomato")
+			return
+		}
+		glv.flashTitle("Created branch "+name+" at "+shortHash(hash), "forestgreen")
+	})
+}
+
+// matchesSearch reports whether a commit matches the search query (case-insensitive)
+func matchesSearch(entry git.LogEntry, query string) bool {
+	query = strings.ToLower(query)
+	for _, text := range append([]string{entry.Subject, entry.Author, entry.Hash}, entry.Refs...) {
+		if strings.Contains(strings.ToLower(text), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// promptSearch asks for a search query and jumps to the first match below the cursor
+func (glv *GitLogView) promptSearch() {
+	showInputPrompt(glv.app, glv.flex, "/", glv.searchQuery, glv.logView, func(query string) {
+		glv.searchQuery = query
+		glv.searchFrom(glv.currentLine, 1)
+	})
+}
+
+// searchNext jumps to the next (1) or previous (-1) match of the search
+func (glv *GitLogView) searchNext(direction int) {
+	if glv.searchQuery == "" {
+		return
+	}
+	glv.searchFrom(glv.currentLine+direction, direction)
+}
+
+// searchFrom selects the first match from line in direction, wrapping around
+// the loaded commits
+func (glv *GitLogView) searchFrom(line, direction int) {
+	count := len(glv.logEntries)
+	if count == 0 {
+		return
+	}
+	for i := 0; i < count; i++ {
+		idx := ((line+direction*i)%count + count) % count
+		if !matchesSearch(glv.logEntries[idx], glv.searchQuery) {
+			continue
+		}
+		glv.moveTo(idx)
+
+		matchNumber, matchCount := 0, 0
+		for j, entry := range glv.logEntries {
+			if matchesSearch(entry, glv.searchQuery) {
+				matchCount++
+				if j <= idx {
+					matchNumber = matchCount
+				}
+			}
+		}
+		glv.logView.SetTitle(tview.Escape(fmt.Sprintf("/%s [%d/%d]", glv.searchQuery, matchNumber, matchCount)))
+		return
+	}
+	glv.flashTitle("/"+glv.searchQuery+" [no match]", "tomato")
+}
+
+// showFilterForm shows a form to filter the log by author, message, path,
+// date range and branch
+func (glv *GitLogView) showFilterForm() {
+	filter := glv.filter
+	form := tview.NewForm().
+		AddInputField("Author", filter.Author, 40, nil, func(text string) { filter.Author = text }).
+		AddInputField("Message", filter.Message, 40, nil, func(text string) { filter.Message = text }).
+		AddInputField("Path", filter.Path, 40, nil, func(text string) { filter.Path = text }).
+		AddInputField("Since", filter.Since, 40, nil, func(text string) { filter.Since = text }).
+		AddInputField("Until", filter.Until, 40, nil, func(text string) { filter.Until = text }).
+		AddInputField("Branch", filter.Branch, 40, nil, func(text string) { filter.Branch = text }).
+		AddCheckbox("All refs", filter.All, func(checked bool) { filter.All = checked })
+	form.AddButton("Apply", func() {
+		filter.Author = strings.TrimSpace(filter.Author)
+		filter.Message = strings.TrimSpace(filter.Message)
+		filter.Path = strings.TrimSpace(filter.Path)
+		filter.Since = strings.TrimSpace(filter.Since)
+		filter.Until = strings.TrimSpace(filter.Until)
+		filter.Branch = strings.TrimSpace(filter.Branch)
+		glv.applyFilter(filter)
+	})
+	form.AddButton("Clear", func() {
+		glv.applyFilter(git.LogFilter{})
+	})
+	form.AddButton("Cancel", glv.backToLog)
+	form.SetCancelFunc(glv.backToLog)
+	form.SetBorder(true).SetTitle("Filter log (Since/Until: e.g. 2024-01-01, 2 weeks ago)")
+	form.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+	form.SetFieldBackgroundColor(tcell.ColorDarkSlateGray)
+
+	glv.flex.Clear()
+	glv.flex.AddItem(form, 0, 1, true)
+	glv.app.SetFocus(form)
+}
+
+// applyFilter reloads the log with filter and returns to the list
+func (glv *GitLogView) applyFilter(filter git.LogFilter) {
+	glv.filter = filter
+	glv.searchQuery = ""
+	glv.logView.SetTitle(glv.title())
+	glv.backToLog()
+	glv.loadGitLog()
+}
+
 func (glv *GitLogView) quitApplication() {
 	go func() {
 		time.Sleep(100 * time.Millisecond)
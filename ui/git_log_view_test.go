@@ -0,0 +1,19 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/sukechannnn/giff/git"
+)
+
+func TestMatchesSearch(t *testing.T) {
+	entry := git.LogEntry{Hash: "abc123", Author: "Alice", Refs: []string{"tag: v1.0"}, Subject: "Fix parser"}
+	for _, query := range []string{"parser", "ALICE", "abc1", "v1.0"} {
+		if !matchesSearch(entry, query) {
+			t.Errorf("expected %q to match", query)
+		}
+	}
+	if matchesSearch(entry, "bob") {
+		t.Error("expected \"bob\" not to match")
+	}
+}
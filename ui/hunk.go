@@ -0,0 +1,267 @@
+package ui
+
+import (
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/ui/commands"
+)
+
+// diffHunk is the position of a hunk in the unified and split renderers
+type diffHunk struct {
+	Start    int // first line index (excluding diff headers, as used by CommandA)
+	End      int // last line index (inclusive)
+	RowStart int // first row in split view
+	RowEnd   int // last row in split view (inclusive)
+}
+
+// findDiffHunks returns the hunks numbered by numberDiffLines, with the rows
+// they take in the split view content
+func findDiffHunks(diffText string) []diffHunk {
+	oldLineMap, newLineMap, hunks := numberDiffLines(diffText)
+	rows := generateSplitViewContent(diffText, oldLineMap, newLineMap, "").RowLines
+	for i := range hunks {
+		hunks[i].RowStart, hunks[i].RowEnd = -1, -1
+		for row, lines := range rows {
+			if lines[0] < hunks[i].Start || lines[0] > hunks[i].End {
+				continue
+			}
+			if hunks[i].RowStart < 0 {
+				hunks[i].RowStart = row
+			}
+			hunks[i].RowEnd = row
+		}
+	}
+	return hunks
+}
+
+// splitViewRows returns the display lines shown on each row of the split view
+func splitViewRows(diffText string) [][]int {
+	oldLineMap, newLineMap := createLineNumberMapping(diffText)
+	return generateSplitViewContent(diffText, oldLineMap, newLineMap, "").RowLines
+}
+
+// selectionLineRange converts the V selection into a line index range
+// (excluding diff headers) in the current renderer
+func selectionLineRange(ctx *DiffViewContext) (int, int, bool) {
+	start, end := *ctx.selectStart, *ctx.selectEnd
+	if start < 0 || end < 0 {
+		return 0, 0, false
+	}
+	if start > end {
+		start, end = end, start
+	}
+
+	if *ctx.isSplitView {
+		rows := splitViewRows(*ctx.currentDiffText)
+		if start >= len(rows) {
+			return 0, 0, false
+		}
+		end = min(end, len(rows)-1)
+		return rows[start][0], rows[end][len(rows[end])-1], true
+	}
+
+	displayMapping := MapUnifiedDisplayToOriginalIdx(*ctx.currentDiffText, ctx.foldState, *ctx.currentFile, ctx.repoRoot)
+	lineStart, okStart := displayMapping[start]
+	lineEnd, okEnd := displayMapping[end]
+	if !okStart || !okEnd {
+		return 0, 0, false
+	}
+	return lineStart, lineEnd, true
+}
+
+// currentHunkIndex returns the index of the hunk under the cursor, or -1
+func currentHunkIndex(ctx *DiffViewContext, hunks []diffHunk) int {
+	if *ctx.isSplitView {
+		for i, h := range hunks {
+			if *ctx.cursorY >= h.RowStart && *ctx.cursorY <= h.RowEnd {
+				return i
+			}
+		}
+		return -1
+	}
+
+	displayMapping := MapUnifiedDisplayToOriginalIdx(*ctx.currentDiffText, ctx.foldState, *ctx.currentFile, ctx.repoRoot)
+	lineIdx, ok := displayMapping[*ctx.cursorY]
+	if !ok {
+		// On a fold row: use the hunk the folded lines belong to
+		lineIdx = -1
+		for idx := range displayMapping {
+			if idx > *ctx.cursorY && (lineIdx < 0 || idx < lineIdx) {
+				lineIdx = idx
+			}
+		}
+		if lineIdx < 0 {
+			return -1
+		}
+		lineIdx = displayMapping[lineIdx]
+	}
+	for i, h := range hunks {
+		if lineIdx >= h.Start && lineIdx <= h.End {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveToHunk moves the cursor to the start of the next (direction > 0) or previous hunk
+func moveToHunk(ctx *DiffViewContext, direction int) {
+	hunks := findDiffHunks(*ctx.currentDiffText)
+	if len(hunks) == 0 {
+		return
+	}
+
+	// Cursor position of each hunk start in the current renderer
+	starts := make([]int, len(hunks))
+	if *ctx.isSplitView {
+		for i, h := range hunks {
+			starts[i] = h.RowStart
+		}
+	} else {
+		displayMapping := MapUnifiedDisplayToOriginalIdx(*ctx.currentDiffText, ctx.foldState, *ctx.currentFile, ctx.repoRoot)
+		rowOf := make(map[int]int, len(displayMapping))
+		for displayIdx, lineIdx := range displayMapping {
+			rowOf[lineIdx] = displayIdx
+		}
+		for i, h := range hunks {
+			starts[i] = rowOf[h.Start]
+		}
+	}
+
+	target := -1
+	if direction > 0 {
+		for _, start := range starts {
+			if start > *ctx.cursorY {
+				target = start
+				break
+			}
+		}
+	} else {
+		for i := len(starts) - 1; i >= 0; i-- {
+			if starts[i] < *ctx.cursorY {
+				target = starts[i]
+				break
+			}
+		}
+	}
+	if target < 0 {
+		return
+	}
+
+	*ctx.cursorY = target
+	*ctx.isSelecting = false
+	*ctx.selectStart = -1
+	*ctx.selectEnd = -1
+	if ctx.viewUpdater != nil {
+		ctx.viewUpdater.UpdateWithCursor(*ctx.currentDiffText, *ctx.cursorY)
+	}
+}
+
+// stageHunkAtCursor stages (or unstages, in the staged diff) the hunk under the cursor
+func stageHunkAtCursor(ctx *DiffViewContext) {
+	hunks := findDiffHunks(*ctx.currentDiffText)
+	idx := currentHunkIndex(ctx, hunks)
+	if idx < 0 {
+		ctx.updateGlobalStatus("No hunk under the cursor", "yellow")
+		return
+	}
+	runCommandA(ctx, hunks[idx].Start, hunks[idx].End)
+}
+
+// discardAtCursor discards the selected lines, or the hunk under the cursor
+// when nothing is selected, from the working tree after confirmation
+func discardAtCursor(ctx *DiffViewContext) {
+	if *ctx.isSelecting {
+		start, end, ok := selectionLineRange(ctx)
+		if !ok {
+			ctx.updateGlobalStatus("No lines selected", "yellow")
+			return
+		}
+		confirmDiscardLines(ctx, start, end, "the selected lines")
+		return
+	}
+
+	hunks := findDiffHunks(*ctx.currentDiffText)
+	idx := currentHunkIndex(ctx, hunks)
+	if idx < 0 {
+		ctx.updateGlobalStatus("No hunk under the cursor", "yellow")
+		return
+	}
+	confirmDiscardLines(ctx, hunks[idx].Start, hunks[idx].End, "this hunk")
+}
+
+// confirmDiscardLines asks for confirmation, then reverts lines start..end
+// (indices excluding diff headers) of the unstaged diff in the working tree
+func confirmDiscardLines(ctx *DiffViewContext, start, end int, what string) {
+	if *ctx.currentStatus != "unstaged" {
+		ctx.updateGlobalStatus("Only unstaged changes can be discarded", "tomato")
+		return
+	}
+
+	// Focus target to return to after the modal closes
+	var focusView tview.Primitive = ctx.diffView
+	if *ctx.isSplitView {
+		focusView = ctx.splitViewFlex
+	}
+
+	modal := tview.NewModal().
+		SetText("Discard " + what + " in " + *ctx.currentFile + "?").
+		AddButtons([]string{"Discard", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			// Return to the original view
+			ctx.app.SetRoot(ctx.mainView, true)
+			ctx.app.SetFocus(focusView)
+			if buttonLabel == "Discard" {
+				discardLines(ctx, start, end)
+			}
+		})
+
+	// Display mainView fullscreen with modal overlay
+	pages := tview.NewPages().
+		AddPage("main", ctx.mainView, true, true).
+		AddPage("modal", modal, true, true)
+	ctx.app.SetRoot(pages, true)
+}
+
+// discardLines reverts lines start..end of the unstaged diff and refreshes the views
+func discardLines(ctx *DiffViewContext, start, end int) {
+	params := commands.CommandDiscardLinesParams{
+		SelectStart:     start,
+		SelectEnd:       end,
+		CurrentFile:     *ctx.currentFile,
+		CurrentStatus:   *ctx.currentStatus,
+		CurrentDiffText: *ctx.currentDiffText,
+		RepoRoot:        ctx.repoRoot,
+		DiscardLog:      discardLog,
+	}
+
+	var result *commands.CommandDiscardLinesResult
+	err := journalAction(ctx.repoRoot, "discard lines in "+*ctx.currentFile, []string{*ctx.currentFile}, func() error {
+		var err error
+		result, err = commands.CommandDiscardLines(params)
+		return err
+	})
+	if err != nil {
+		ctx.updateGlobalStatus(err.Error(), "tomato")
+		return
+	}
+
+	if result.ShouldUpdate {
+		// No unstaged changes left in this file
+		if ctx.onUpdate != nil {
+			ctx.onUpdate()
+		}
+		updateGlobalStatus("Changes discarded (D to restore)", "forestgreen")
+		return
+	}
+
+	*ctx.currentDiffText = result.NewDiffText
+	*ctx.isSelecting = false
+	*ctx.selectStart = -1
+	*ctx.selectEnd = -1
+	*ctx.cursorY = result.NewCursorPos
+	if ctx.viewUpdater != nil {
+		ctx.viewUpdater.UpdateWithCursor(*ctx.currentDiffText, *ctx.cursorY)
+	}
+	ctx.refreshFileList()
+	ctx.updateFileListView()
+	ctx.updateGlobalStatus("Changes discarded (D to restore)", "forestgreen")
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindDiffHunks(t *testing.T) {
+	diffText := `diff --git a/file.txt b/file.txt
+index 1234567..abcdefg 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1,4 +1,4 @@
+ line1
+-old2
+-old3
++new2
+ line4
+@@ -10,3 +10,4 @@
+ line10
++added1
++added2
+ line12`
+
+	want := []diffHunk{
+		{Start: 0, End: 4, RowStart: 0, RowEnd: 3},
+		{Start: 5, End: 8, RowStart: 4, RowEnd: 7},
+	}
+	if got := findDiffHunks(diffText); !reflect.DeepEqual(got, want) {
+		t.Errorf("findDiffHunks() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindDiffHunks_NoHunks(t *testing.T) {
+	if got := findDiffHunks(""); len(got) != 0 {
+		t.Errorf("findDiffHunks(\"\") = %+v, want none", got)
+	}
+}
+
+func TestSplitViewRows(t *testing.T) {
+	diffText := `@@ -1,4 +1,3 @@
+ line1
+-old2
+-old3
++new2
+ line4`
+
+	want := [][]int{{0}, {1, 3}, {2}, {4}}
+	if got := splitViewRows(diffText); !reflect.DeepEqual(got, want) {
+		t.Errorf("splitViewRows() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/sukechannnn/giff/git"
+	"github.com/sukechannnn/giff/util"
+)
+
+// graphCell is one column of the commit graph
+type graphCell struct {
+	glyph string
+	color util.ColorColde
+}
+
+// graphRow is the graph drawn left of a commit: two cells per lane, the
+// lane itself and the gap to the next lane
+type graphRow struct {
+	cells []graphCell
+	lane  int // lane of the commit
+}
+
+// buildLogGraph lays out the commits in lanes from their parent hashes.
+// Entries must list children before their parents (git log --date-order).
+// Each lane keeps its color for as long as it follows the same line of history.
+func buildLogGraph(entries []git.LogEntry) []graphRow {
+	var lanes []string // hash expected next in each lane ("" = free)
+	var colors []util.ColorColde
+	nextColor := 0
+	openLane := func(hash string) int {
+		color := util.GraphLaneColors[nextColor%len(util.GraphLaneColors)]
+		nextColor++
+		for i, h := range lanes {
+			if h == "" {
+				lanes[i] = hash
+				colors[i] = color
+				return i
+			}
+		}
+		lanes = append(lanes, hash)
+		colors = append(colors, color)
+		return len(lanes) - 1
+	}
+
+	rows := make([]graphRow, len(entries))
+	for n, entry := range entries {
+		col := indexOf(lanes, entry.Hash)
+		if col < 0 {
+			col = openLane(entry.Hash)
+		}
+		before := append([]string(nil), lanes...)
+
+		// Other lanes waiting for this commit end here
+		converging := map[int]bool{}
+		for i, h := range lanes {
+			if i != col && h == entry.Hash {
+				converging[i] = true
+			}
+		}
+
+		// The first parent continues the lane, other parents join or open lanes
+		forking := map[int]bool{}
+		joining := map[int]bool{}
+		if len(entry.Parents) == 0 {
+			lanes[col] = ""
+		} else {
+			lanes[col] = entry.Parents[0]
+		}
+		for _, parent := range entry.Parents[min(1, len(entry.Parents)):] {
+			if i := indexOf(lanes, parent); i >= 0 && !converging[i] {
+				joining[i] = true
+				continue
+			}
+			forking[openLane(parent)] = true
+		}
+		for i := range converging {
+			lanes[i] = ""
+		}
+
+		// Horizontal span of the connections drawn on this row
+		lo, hi := col, col
+		for _, set := range []map[int]bool{converging, forking, joining} {
+			for i := range set {
+				lo = min(lo, i)
+				hi = max(hi, i)
+			}
+		}
+
+		width := max(len(before), len(lanes))
+		cells := make([]graphCell, 0, width*2)
+		for i := 0; i < width; i++ {
+			var cell graphCell
+			switch {
+			case i == col:
+				cell = graphCell{"●", colors[col]}
+			case converging[i] && i > col:
+				cell = graphCell{"╯", colors[i]}
+			case converging[i]:
+				cell = graphCell{"╰", colors[i]}
+			case forking[i] && i > col:
+				cell = graphCell{"╮", colors[i]}
+			case forking[i]:
+				cell = graphCell{"╭", colors[i]}
+			case joining[i] && i > col:
+				cell = graphCell{"┤", colors[i]}
+			case joining[i]:
+				cell = graphCell{"├", colors[i]}
+			case i < len(before) && before[i] != "" && i > lo && i < hi:
+				cell = graphCell{"┼", colors[i]}
+			case i < len(before) && before[i] != "":
+				cell = graphCell{"│", colors[i]}
+			case i > lo && i < hi:
+				cell = graphCell{"─", colors[col]}
+			default:
+				cell = graphCell{" ", ""}
+			}
+			cells = append(cells, cell)
+
+			if i >= lo && i < hi {
+				cells = append(cells, graphCell{"─", colors[col]})
+			} else {
+				cells = append(cells, graphCell{" ", ""})
+			}
+		}
+		rows[n] = graphRow{cells: cells, lane: col}
+
+		// Drop free lanes at the right edge
+		for len(lanes) > 0 && lanes[len(lanes)-1] == "" {
+			lanes = lanes[:len(lanes)-1]
+			colors = colors[:len(colors)-1]
+		}
+	}
+	return rows
+}
+
+// indexOf returns the index of s in list, or -1
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// visibleCells returns the cells without the trailing blanks
+func (r graphRow) visibleCells() []graphCell {
+	end := len(r.cells)
+	for end > 0 && r.cells[end-1].glyph == " " {
+		end--
+	}
+	return r.cells[:end]
+}
+
+// render draws the graph row; the commit node is hollow when it is not an
+// ancestor of the selected commit
+func (r graphRow) render(ancestor bool) string {
+	var sb strings.Builder
+	for i, cell := range r.visibleCells() {
+		glyph := cell.glyph
+		if i == r.lane*2 && !ancestor {
+			glyph = "○"
+		}
+		if cell.color == "" {
+			sb.WriteString(glyph)
+			continue
+		}
+		sb.WriteString("[" + string(cell.color) + "]" + glyph)
+	}
+	sb.WriteString("[-]")
+	return sb.String()
+}
+
+// String returns the row without colors
+func (r graphRow) String() string {
+	var sb strings.Builder
+	for _, cell := range r.visibleCells() {
+		sb.WriteString(cell.glyph)
+	}
+	return sb.String()
+}
+
+// logAncestry returns the hashes of the loaded commits reachable from hash
+func logAncestry(entries []git.LogEntry, hash string) map[string]bool {
+	parents := make(map[string][]string, len(entries))
+	for _, entry := range entries {
+		parents[entry.Hash] = entry.Parents
+	}
+	ancestry := map[string]bool{}
+	queue := []string{hash}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if ancestry[current] {
+			continue
+		}
+		ancestry[current] = true
+		queue = append(queue, parents[current]...)
+	}
+	return ancestry
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sukechannnn/giff/git"
+)
+
+func graphStrings(rows []graphRow) []string {
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = row.String()
+	}
+	return lines
+}
+
+func TestBuildLogGraph_Linear(t *testing.T) {
+	entries := []git.LogEntry{
+		{Hash: "c", Parents: []string{"b"}},
+		{Hash: "b", Parents: []string{"a"}},
+		{Hash: "a"},
+	}
+	want := []string{"●", "●", "●"}
+	if got := graphStrings(buildLogGraph(entries)); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildLogGraph() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildLogGraph_BranchAndMerge(t *testing.T) {
+	entries := []git.LogEntry{
+		{Hash: "m", Parents: []string{"c", "b"}}, // merge of topic
+		{Hash: "c", Parents: []string{"a"}},      // main
+		{Hash: "b", Parents: []string{"a"}},      // topic
+		{Hash: "a"},
+	}
+	want := []string{
+		"●─╮",
+		"● │",
+		"│ ●",
+		"●─╯",
+	}
+	rows := buildLogGraph(entries)
+	if got := graphStrings(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildLogGraph() = %q, want %q", got, want)
+	}
+
+	// Lanes keep their color down the same line of history
+	if rows[0].cells[0].color != rows[1].cells[0].color || rows[0].cells[2].color != rows[2].cells[2].color {
+		t.Errorf("expected stable lane colors, got %+v", rows)
+	}
+	if rows[0].cells[0].color == rows[0].cells[2].color {
+		t.Errorf("expected different colors for different lanes, got %+v", rows[0])
+	}
+}
+
+func TestBuildLogGraph_TwoBranchTips(t *testing.T) {
+	// Two branches whose tips are listed first (git log --all)
+	entries := []git.LogEntry{
+		{Hash: "b", Parents: []string{"a"}},
+		{Hash: "c", Parents: []string{"a"}},
+		{Hash: "a"},
+	}
+	want := []string{
+		"●",
+		"│ ●",
+		"●─╯",
+	}
+	if got := graphStrings(buildLogGraph(entries)); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildLogGraph() = %q, want %q", got, want)
+	}
+}
+
+func TestLogAncestry(t *testing.T) {
+	entries := []git.LogEntry{
+		{Hash: "m", Parents: []string{"c", "b"}},
+		{Hash: "c", Parents: []string{"a"}},
+		{Hash: "b", Parents: []string{"a"}},
+		{Hash: "a"},
+	}
+	want := map[string]bool{"b": true, "a": true}
+	if got := logAncestry(entries, "b"); !reflect.DeepEqual(got, want) {
+		t.Errorf("logAncestry() = %v, want %v", got, want)
+	}
+	if got := logAncestry(entries, "m"); len(got) != 4 {
+		t.Errorf("expected every commit in the ancestry of the merge, got %v", got)
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+)
+
+// operationBanner returns the title part of the status bar that shows op and
+// the keys to move it on, or "" when no operation is in progress
+func operationBanner(op *git.Operation) string {
+	if op == nil {
+		return ""
+	}
+	keys := []string{}
+	if op.CanContinue() {
+		keys = append(keys, "C:continue")
+	}
+	if op.CanSkip() {
+		keys = append(keys, "S:skip")
+	}
+	keys = append(keys, "X:abort")
+	return "[black:yellow] " + strings.ToUpper(op.String()) + " [-:-] " + strings.Join(keys, "  ")
+}
+
+// continueOperation runs git <operation> --continue, --skip or --abort for the
+// operation in progress. A skip or an abort is confirmed first since it
+// throws away the current commit's changes or the resolved conflicts.
+func continueOperation(ctx *FileListKeyContext, verb string) {
+	op := git.CurrentOperation(ctx.repoRoot)
+	if op == nil {
+		ctx.updateGlobalStatus("No merge, rebase, cherry-pick, revert or bisect in progress", "yellow")
+		return
+	}
+
+	var action func(string, *git.Operation) error
+	switch verb {
+	case "continue":
+		action = git.ContinueOperation
+	case "skip":
+		action = git.SkipOperation
+	case "abort":
+		action = git.AbortOperation
+	}
+	run := func() {
+		err := action(ctx.repoRoot, op)
+		ctx.refreshFileList()
+		ctx.updateFileListView()
+		ctx.updateSelectedFileDiff()
+		ctx.updateStatusTitle()
+		if err != nil {
+			ctx.updateGlobalStatus(err.Error(), "tomato")
+			return
+		}
+		next := git.CurrentOperation(ctx.repoRoot)
+		switch {
+		case verb == "abort":
+			ctx.updateGlobalStatus("Aborted the "+op.Name, "forestgreen")
+		case op.Name == "bisect":
+			ctx.updateGlobalStatus("Skipped the commit under test", "forestgreen")
+		case next != nil && next.Name == op.Name:
+			ctx.updateGlobalStatus("The "+op.Name+" stopped again at "+next.String()+": resolve the conflicts, then continue", "yellow")
+		default:
+			ctx.updateGlobalStatus("The "+op.Name+" is complete", "forestgreen")
+		}
+	}
+	var confirmText, button string
+	switch {
+	case verb == "abort":
+		confirmText = "Abort the " + op.String() + "?\nThe repository goes back to where it started and resolved conflicts are lost."
+		button = "Abort"
+	case verb == "skip" && op.Name == "bisect":
+		confirmText = "Skip the commit under test?\nBisect moves on to another commit."
+		button = "Skip"
+	case verb == "skip":
+		confirmText = "Skip the current commit of the " + op.String() + "?\nIts changes and any resolved conflicts are dropped."
+		button = "Skip"
+	default:
+		run()
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText(confirmText).
+		AddButtons([]string{button, "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			ctx.app.SetRoot(ctx.mainView, true)
+			ctx.app.SetFocus(ctx.fileListView)
+			if buttonLabel == button {
+				run()
+			}
+		})
+	pages := tview.NewPages().
+		AddPage("main", ctx.mainView, true, true).
+		AddPage("modal", modal, true, true)
+	ctx.app.SetRoot(pages, true)
+}
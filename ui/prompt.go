@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/util"
+)
+
+// showInputPrompt adds a one-line input to the bottom of flex. Enter calls
+// onSubmit with the trimmed text (unless it is empty), Esc cancels; either way
+// the input is removed and focus goes back to returnFocus.
+func showInputPrompt(app *tview.Application, flex *tview.Flex, label, initial string, returnFocus tview.Primitive, onSubmit func(string)) {
+	input := tview.NewInputField().
+		SetLabel(label).
+		SetText(initial).
+		SetFieldBackgroundColor(util.BackgroundColor.ToTcellColor()).
+		SetLabelColor(tcell.ColorAqua)
+	input.SetBorder(true)
+	input.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		text := strings.TrimSpace(input.GetText())
+		flex.RemoveItem(input)
+		app.SetFocus(returnFocus)
+		if key == tcell.KeyEnter && text != "" {
+			onSubmit(text)
+		}
+	})
+
+	flex.AddItem(input, 3, 0, true)
+	app.SetFocus(input)
+}
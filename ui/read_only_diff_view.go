@@ -0,0 +1,425 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/util"
+)
+
+// readOnlyDiffKeyMessage is the key help of a read-only diff layout opened from another view
+var readOnlyDiffKeyMessage = "j/k:move  /:search  e:fold  s:split  V:select  C-y:copy  Esc:back  q:quit"
+
+// ReadOnlyDiffLayoutParams contains parameters for NewReadOnlyDiffLayout
+type ReadOnlyDiffLayoutParams struct {
+	App           *tview.Application
+	RepoRoot      string
+	Files         []FileEntry
+	FileListTitle string
+	DiffViewTitle string
+	StatusMessage string
+	MainView      tview.Primitive // view shown behind modals opened from the file list
+
+	// LoadDiff returns the diff text of a file in this layout
+	LoadDiff func(filePath string, ignoreWhitespace bool) string
+	// OnEsc is called when Esc is pressed in the file list
+	OnEsc func()
+}
+
+// ReadOnlyDiffLayout is a file tree + diff view layout that cannot modify the repository.
+// It is shared by every view that shows a comparison (commit details, revision ranges, ...).
+type ReadOnlyDiffLayout struct {
+	View         *tview.Flex
+	FileListView *tview.TextView
+	StatusView   *tview.TextView
+
+	statusMessage string
+	currentFile   *string
+	setFiles      func([]FileEntry)
+}
+
+// SetStatusMessage replaces the default key help shown in the status bar
+func (l *ReadOnlyDiffLayout) SetStatusMessage(message string) {
+	l.statusMessage = message
+	l.StatusView.SetText(message)
+}
+
+// CurrentFile returns the path of the file whose diff is displayed
+func (l *ReadOnlyDiffLayout) CurrentFile() string {
+	return *l.currentFile
+}
+
+// SetFiles replaces the listed files and shows the diff of the first one,
+// keeping the focused pane
+func (l *ReadOnlyDiffLayout) SetFiles(files []FileEntry) {
+	l.setFiles(files)
+}
+
+// NewReadOnlyDiffLayout builds the file tree + diff layout for a fixed list of files
+func NewReadOnlyDiffLayout(params ReadOnlyDiffLayoutParams) *ReadOnlyDiffLayout {
+	app := params.App
+	repoRoot := params.RepoRoot
+
+	fileListTitle := params.FileListTitle
+	if fileListTitle == "" {
+		fileListTitle = "Changed Files"
+	}
+	diffViewTitle := params.DiffViewTitle
+	if diffViewTitle == "" {
+		diffViewTitle = "File Diff"
+	}
+
+	// Create UI components (same pattern as root_editor.go)
+	fileListView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false)
+	fileListView.SetBorder(true).SetTitle(fileListTitle)
+	fileListView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	diffView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false)
+	diffView.SetBorder(true).SetTitle(diffViewTitle)
+	diffView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+	diffView.SetBorderStyle(tcell.StyleDefault)
+
+	unifiedViewFlex := tview.NewFlex().
+		AddItem(diffView, 0, 1, false)
+	unifiedViewFlex.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	beforeView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false)
+	beforeView.SetBorder(true).SetTitle("Before")
+	beforeView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	afterView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false)
+	afterView.SetBorder(true).SetTitle("After")
+	afterView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	splitViewFlex := tview.NewFlex().
+		AddItem(beforeView, 0, 1, false).
+		AddItem(afterView, 0, 1, false)
+	splitViewFlex.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	// Status bar
+	statusView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignLeft).
+		SetWrap(false)
+	statusView.SetBorder(true)
+	statusView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+	statusView.SetText(params.StatusMessage)
+
+	contentFlex := tview.NewFlex()
+	contentFlex.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+	contentFlex.
+		AddItem(fileListView, 0, FileListFlexRatio, true).
+		AddItem(unifiedViewFlex, 0, DiffViewFlexRatio, false)
+
+	// Vertical layout with status bar + content
+	mainFlex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(statusView, 3, 0, false).
+		AddItem(contentFlex, 0, 1, true)
+	mainFlex.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	// State variables
+	files := params.Files
+	var fileList []FileEntry
+	lineNumberMap := make(map[int]int)
+	dirCollapseState := NewDirCollapseState()
+	var currentSelection int
+	var cursorY int
+	var currentFile string
+	var currentStatus string
+	var currentDiffText string
+	var isSplitView bool
+	var leftPaneFocused = true
+	var isSelecting bool
+	var selectStart = -1
+	var selectEnd = -1
+	var preserveScrollRow = -1
+	var ignoreWhitespace bool
+	var gPressed bool
+	var lastGTime time.Time
+	var searchQuery string
+	var searchMatches []int
+	var searchMatchIndex = -1
+	var isSearchMode bool
+	var searchInput string
+	var searchCursorYBeforeSearch int
+	foldState := NewFoldState()
+
+	layout := &ReadOnlyDiffLayout{
+		View:          mainFlex,
+		FileListView:  fileListView,
+		StatusView:    statusView,
+		statusMessage: params.StatusMessage,
+		currentFile:   &currentFile,
+	}
+
+	mainView := params.MainView
+	if mainView == nil {
+		mainView = mainFlex
+	}
+
+	// Diff retrieval callback
+	updateDiffText := func(filePath, status, repo string, out *string, ignoreWhitespace bool) {
+		*out = params.LoadDiff(filePath, ignoreWhitespace)
+	}
+
+	updateStatus := func(message string, color string) {
+		statusView.SetText(fmt.Sprintf("[%s]%s[-]", color, message))
+		go func() {
+			time.Sleep(5 * time.Second)
+			app.QueueUpdateDraw(func() {
+				statusView.SetText(layout.statusMessage)
+			})
+		}()
+	}
+
+	// Build file list
+	buildFileListContent := func(focusedPane bool) string {
+		return BuildFileListContentForCommit(
+			files,
+			currentSelection,
+			focusedPane,
+			&fileList,
+			lineNumberMap,
+			dirCollapseState,
+		)
+	}
+
+	updateFileListView := func() {
+		currentRow, currentCol := fileListView.GetScrollOffset()
+		shouldPreserveScroll := preserveScrollRow >= 0
+		if shouldPreserveScroll {
+			currentRow = preserveScrollRow
+			preserveScrollRow = -1
+		}
+
+		fileListView.Clear()
+		fileListView.SetText(buildFileListContent(leftPaneFocused))
+
+		if shouldPreserveScroll {
+			fileListView.ScrollTo(currentRow, currentCol)
+		} else {
+			if actualLine, exists := lineNumberMap[currentSelection]; exists {
+				_, _, _, height := fileListView.GetInnerRect()
+				if actualLine >= currentRow+height-1 {
+					fileListView.ScrollTo(actualLine-height+2, currentCol)
+				} else if actualLine < currentRow {
+					if currentSelection == 0 {
+						fileListView.ScrollTo(0, currentCol)
+					} else {
+						fileListView.ScrollTo(actualLine, currentCol)
+					}
+				} else {
+					fileListView.ScrollTo(currentRow, currentCol)
+				}
+			}
+		}
+	}
+
+	updateSelectedFileDiff := func() {
+		if len(fileList) == 0 {
+			currentDiffText = ""
+			currentFile = ""
+			currentStatus = ""
+			if isSplitView {
+				beforeView.SetText("")
+				afterView.SetText("No files changed")
+			} else {
+				diffView.SetText("No files changed")
+			}
+			return
+		}
+
+		if currentSelection < 0 {
+			currentSelection = 0
+		} else if currentSelection >= len(fileList) {
+			currentSelection = len(fileList) - 1
+		}
+
+		fileEntry := fileList[currentSelection]
+
+		if fileEntry.IsDirectory {
+			currentDiffText = ""
+			currentFile = ""
+			currentStatus = ""
+			dirText := "dir: " + fileEntry.Path + "/"
+			if isSplitView {
+				beforeView.SetText("")
+				afterView.SetText(dirText)
+			} else {
+				diffView.SetText(dirText)
+			}
+			return
+		}
+
+		currentFile = fileEntry.Path
+		currentStatus = fileEntry.StageStatus
+
+		cursorY = 0
+		isSelecting = false
+		selectStart = -1
+		selectEnd = -1
+
+		updateDiffText(currentFile, currentStatus, repoRoot, &currentDiffText, ignoreWhitespace)
+
+		if isSplitView {
+			updateSplitViewWithoutCursor(beforeView, afterView, currentDiffText, currentFile)
+		} else {
+			updateDiffViewWithoutCursor(diffView, currentDiffText, foldState, currentFile, repoRoot)
+		}
+	}
+
+	// If the selection is a directory, select the first file
+	selectFirstFile := func() {
+		if currentSelection < len(fileList) && fileList[currentSelection].IsDirectory {
+			for i, entry := range fileList {
+				if !entry.IsDirectory {
+					currentSelection = i
+					updateFileListView()
+					break
+				}
+			}
+		}
+	}
+
+	updateFileListView()
+	selectFirstFile()
+	updateSelectedFileDiff()
+
+	// Build DiffViewContext
+	diffViewContext := &DiffViewContext{
+		diffView:        diffView,
+		fileListView:    fileListView,
+		beforeView:      beforeView,
+		afterView:       afterView,
+		splitViewFlex:   splitViewFlex,
+		unifiedViewFlex: unifiedViewFlex,
+		contentFlex:     contentFlex,
+		app:             app,
+
+		cursorY:               &cursorY,
+		selectStart:           &selectStart,
+		selectEnd:             &selectEnd,
+		isSelecting:           &isSelecting,
+		isSplitView:           &isSplitView,
+		leftPaneFocused:       &leftPaneFocused,
+		currentDiffText:       &currentDiffText,
+		currentFile:           &currentFile,
+		currentStatus:         &currentStatus,
+		savedTargetFile:       new(string),
+		preferUnstagedSection: new(bool),
+		currentSelection:      &currentSelection,
+		fileList:              &fileList,
+		preserveScrollRow:     &preserveScrollRow,
+		ignoreWhitespace:      &ignoreWhitespace,
+
+		repoRoot:  repoRoot,
+		patchPath: "",
+
+		gPressed:  &gPressed,
+		lastGTime: &lastGTime,
+
+		viewUpdater: &UnifiedViewUpdater{
+			diffView:    diffView,
+			foldState:   foldState,
+			filePath:    &currentFile,
+			repoRoot:    repoRoot,
+			searchQuery: &searchQuery,
+		},
+
+		foldState: foldState,
+
+		searchQuery:               &searchQuery,
+		searchMatches:             &searchMatches,
+		searchMatchIndex:          &searchMatchIndex,
+		isSearchMode:              &isSearchMode,
+		searchInput:               &searchInput,
+		searchCursorYBeforeSearch: &searchCursorYBeforeSearch,
+
+		readOnly: true,
+
+		updateFileListView: updateFileListView,
+		updateGlobalStatus: updateStatus,
+		setGlobalStatusText: func(text string) {
+			statusView.SetText(text)
+		},
+		refreshFileList:       func() {},
+		onUpdate:              nil,
+		updateCurrentDiffText: updateDiffText,
+		updateStatusTitle:     func() {},
+		onEsc:                 params.OnEsc,
+	}
+	SetupDiffViewKeyBindings(diffViewContext)
+
+	layout.setFiles = func(newFiles []FileEntry) {
+		files = newFiles
+		currentSelection = 0
+		updateFileListView()
+		selectFirstFile()
+		updateSelectedFileDiff()
+		if !leftPaneFocused && diffViewContext.viewUpdater != nil {
+			diffViewContext.viewUpdater.UpdateWithCursor(currentDiffText, cursorY)
+		}
+	}
+
+	// Build FileListKeyContext
+	fileListKeyContext := &FileListKeyContext{
+		fileListView:    fileListView,
+		diffView:        diffView,
+		beforeView:      beforeView,
+		afterView:       afterView,
+		splitViewFlex:   splitViewFlex,
+		unifiedViewFlex: unifiedViewFlex,
+		contentFlex:     contentFlex,
+		app:             app,
+		mainView:        mainView,
+
+		currentSelection:  &currentSelection,
+		cursorY:           &cursorY,
+		isSelecting:       &isSelecting,
+		selectStart:       &selectStart,
+		selectEnd:         &selectEnd,
+		isSplitView:       &isSplitView,
+		leftPaneFocused:   &leftPaneFocused,
+		currentFile:       &currentFile,
+		currentStatus:     &currentStatus,
+		currentDiffText:   &currentDiffText,
+		preserveScrollRow: &preserveScrollRow,
+		ignoreWhitespace:  &ignoreWhitespace,
+		filterQuery:       new(string),
+
+		fileList:         &fileList,
+		dirCollapseState: dirCollapseState,
+		repoRoot:         repoRoot,
+		diffViewContext:  diffViewContext,
+
+		readOnly: true,
+
+		updateFileListView:     updateFileListView,
+		updateSelectedFileDiff: updateSelectedFileDiff,
+		refreshFileList:        func() {},
+		updateCurrentDiffText:  updateDiffText,
+		updateGlobalStatus:     updateStatus,
+		updateStatusTitle:      func() {},
+		setGlobalStatusText: func(text string) {
+			statusView.SetText(text)
+		},
+		onEsc: params.OnEsc,
+	}
+	SetupFileListKeyBindings(fileListKeyContext)
+
+	return layout
+}
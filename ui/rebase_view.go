@@ -0,0 +1,274 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+	"github.com/sukechannnn/giff/util"
+)
+
+var rebaseViewKeyMessage = "j/k:move  J/K:move commit down/up  p:pick  r:reword  e:edit  s:squash  f:fixup  d:drop  C-e/C-y:scroll diff  Enter:rebase  Esc:cancel  q:quit"
+
+// rebaseActionColors colors the actions in the todo list
+var rebaseActionColors = map[string]string{
+	"pick":   "green",
+	"reword": "aqua",
+	"edit":   "yellow",
+	"squash": "fuchsia",
+	"fixup":  "fuchsia",
+	"drop":   "red",
+}
+
+// RebaseView edits the todo list of an interactive rebase, oldest commit first
+type RebaseView struct {
+	app         *tview.Application
+	listView    *tview.TextView
+	previewView *tview.TextView
+	statusView  *tview.TextView
+	flex        *tview.Flex
+	repoRoot    string
+	commit      string // oldest commit of the rebase
+	items       []git.RebaseTodoItem
+	currentLine int
+	onSave      func(items []git.RebaseTodoItem)
+	onCancel    func()
+}
+
+// NewRebaseView creates a todo list editor for the commits from commit to
+// HEAD. onSave receives the edited todo list.
+func NewRebaseView(app *tview.Application, repoRoot, commit string, onSave func(items []git.RebaseTodoItem), onCancel func()) (*RebaseView, error) {
+	items, err := git.GetRebaseTodo(repoRoot, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	listView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	listView.SetBorder(true).SetTitle(fmt.Sprintf("Rebase %d commits from %s (oldest first)", len(items), shortHash(commit)))
+	listView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	previewView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false).
+		SetScrollable(true)
+	previewView.SetBorder(true)
+	previewView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	statusView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false).
+		SetText(rebaseViewKeyMessage)
+	statusView.SetBorder(true)
+	statusView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(listView, min(len(items)+2, 15), 0, true).
+		AddItem(previewView, 0, 1, false).
+		AddItem(statusView, 3, 0, false)
+
+	rv := &RebaseView{
+		app:         app,
+		listView:    listView,
+		previewView: previewView,
+		statusView:  statusView,
+		flex:        flex,
+		repoRoot:    repoRoot,
+		commit:      commit,
+		items:       items,
+		onSave:      onSave,
+		onCancel:    onCancel,
+	}
+
+	rv.setupKeyBindings()
+	rv.updateList()
+	rv.updatePreview()
+
+	return rv, nil
+}
+
+// GetView returns the main flex view
+func (rv *RebaseView) GetView() tview.Primitive {
+	return rv.flex
+}
+
+// updateList renders the todo list with the current line highlighted
+func (rv *RebaseView) updateList() {
+	var sb strings.Builder
+	for i, item := range rv.items {
+		if i == rv.currentLine {
+			sb.WriteString(fmt.Sprintf("[white:blue]%-6s %s %s[-:-]\n", item.Action, shortHash(item.Hash), tview.Escape(item.Subject)))
+			continue
+		}
+		subject := tview.Escape(item.Subject)
+		if item.Action == "drop" {
+			subject = "[" + string(util.PlaceholderColor) + "]" + subject + "[-]"
+		}
+		sb.WriteString(fmt.Sprintf("[%s]%-6s[-] [yellow]%s[-] %s\n", rebaseActionColors[item.Action], item.Action, shortHash(item.Hash), subject))
+	}
+	rv.listView.SetText(sb.String())
+
+	// Keep the current line visible
+	row, _ := rv.listView.GetScrollOffset()
+	_, _, _, height := rv.listView.GetInnerRect()
+	if rv.currentLine < row {
+		rv.listView.ScrollTo(rv.currentLine, 0)
+	} else if height > 0 && rv.currentLine >= row+height {
+		rv.listView.ScrollTo(rv.currentLine-height+1, 0)
+	}
+}
+
+// updatePreview shows the diff of the commit under the cursor
+func (rv *RebaseView) updatePreview() {
+	if rv.currentLine >= len(rv.items) {
+		return
+	}
+	hash := rv.items[rv.currentLine].Hash
+	rv.previewView.SetTitle(shortHash(hash))
+	rv.previewView.SetText(colorizeCommitPreview(getCommitPreview(rv.repoRoot, hash)))
+	rv.previewView.ScrollToBeginning()
+}
+
+// getCommitPreview returns the message, stat and patch of a commit
+func getCommitPreview(repoRoot, hash string) string {
+	cmd := exec.Command("git", "-c", "core.quotepath=false", "show", "--stat", "--patch",
+		"--format=%an <%ae>, %ar%n%n%B", hash)
+	cmd.Dir = repoRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "Failed to show " + shortHash(hash) + ": " + strings.TrimSpace(string(output))
+	}
+	return string(output)
+}
+
+// colorizeCommitPreview colors the file headers, hunk headers and changed
+// lines of git show output
+func colorizeCommitPreview(text string) string {
+	lines := util.SplitLines(text)
+	inPatch := false
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff --git"):
+			inPatch = true
+			lines[i] = "[::b]" + tview.Escape(line) + "[::-]"
+		case !inPatch:
+			lines[i] = tview.Escape(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = "[aqua]" + tview.Escape(line) + "[-]"
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			lines[i] = "[::b]" + tview.Escape(line) + "[::-]"
+		default:
+			lines[i] = colorizeLineFallback(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// setAction changes the action of the commit under the cursor
+func (rv *RebaseView) setAction(action string) {
+	if rv.currentLine >= len(rv.items) {
+		return
+	}
+	rv.items[rv.currentLine].Action = action
+	rv.updateList()
+}
+
+// moveItem moves the commit under the cursor down (1) or up (-1) the todo list
+func (rv *RebaseView) moveItem(direction int) {
+	next := rv.currentLine + direction
+	if next < 0 || next >= len(rv.items) {
+		return
+	}
+	rv.items[rv.currentLine], rv.items[next] = rv.items[next], rv.items[rv.currentLine]
+	rv.currentLine = next
+	rv.updateList()
+}
+
+// moveCursor moves the cursor to line and previews its commit
+func (rv *RebaseView) moveCursor(line int) {
+	if line < 0 || line >= len(rv.items) || line == rv.currentLine {
+		return
+	}
+	rv.currentLine = line
+	rv.updateList()
+	rv.updatePreview()
+}
+
+// save checks the todo list and hands it over
+func (rv *RebaseView) save() {
+	if err := git.ValidateRebaseTodo(rv.items); err != nil {
+		rv.statusView.SetText("[tomato]" + tview.Escape(err.Error()) + "[-]")
+		return
+	}
+	rv.onSave(rv.items)
+}
+
+// setupKeyBindings configures keyboard navigation for the todo list
+func (rv *RebaseView) setupKeyBindings() {
+	rv.listView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			rv.onCancel()
+			return nil
+		case tcell.KeyEnter:
+			rv.save()
+			return nil
+		case tcell.KeyCtrlE:
+			row, col := rv.previewView.GetScrollOffset()
+			rv.previewView.ScrollTo(row+1, col)
+			return nil
+		case tcell.KeyCtrlY:
+			row, col := rv.previewView.GetScrollOffset()
+			rv.previewView.ScrollTo(max(row-1, 0), col)
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'j':
+			rv.moveCursor(rv.currentLine + 1)
+			return nil
+		case 'k':
+			rv.moveCursor(rv.currentLine - 1)
+			return nil
+		case 'J':
+			rv.moveItem(1)
+			return nil
+		case 'K':
+			rv.moveItem(-1)
+			return nil
+		case 'p':
+			rv.setAction("pick")
+			return nil
+		case 'r':
+			rv.setAction("reword")
+			return nil
+		case 'e':
+			rv.setAction("edit")
+			return nil
+		case 's':
+			rv.setAction("squash")
+			return nil
+		case 'f':
+			rv.setAction("fixup")
+			return nil
+		case 'd':
+			rv.setAction("drop")
+			return nil
+		case 'q':
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				os.Exit(0)
+			}()
+			rv.app.Stop()
+			return nil
+		}
+
+		return event
+	})
+}
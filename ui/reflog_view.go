@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+	"github.com/sukechannnn/giff/util"
+)
+
+var reflogViewKeyMessage = "j/k:move  [/]:prev/next ref  Enter:show  c:checkout  b:branch  x:reset  Esc:back  q:quit"
+
+// ReflogView lists where HEAD or a branch pointed to, to recover lost commits
+type ReflogView struct {
+	app         *tview.Application
+	listView    *tview.TextView
+	statusView  *tview.TextView
+	flex        *tview.Flex
+	repoRoot    string
+	refs        []string // HEAD and the local branches
+	refIndex    int
+	entries     []git.ReflogEntry
+	currentLine int
+	changed     bool // HEAD, the index or the working tree was modified
+	onExit      func(changed bool)
+}
+
+// NewReflogView creates a view of the reflog of HEAD; [ and ] switch to the
+// reflogs of the branches
+func NewReflogView(app *tview.Application, repoRoot string, onExit func(changed bool)) *ReflogView {
+	listView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	listView.SetBorder(true)
+	listView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	statusView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false).
+		SetText(reflogViewKeyMessage)
+	statusView.SetBorder(true)
+	statusView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(listView, 0, 1, true).
+		AddItem(statusView, 3, 0, false)
+
+	refs := []string{"HEAD"}
+	if branches, err := git.ListBranches(repoRoot); err == nil {
+		for _, branch := range branches {
+			refs = append(refs, branch.Name)
+		}
+	}
+
+	rv := &ReflogView{
+		app:        app,
+		listView:   listView,
+		statusView: statusView,
+		flex:       flex,
+		repoRoot:   repoRoot,
+		refs:       refs,
+		onExit:     onExit,
+	}
+
+	rv.setupKeyBindings()
+	rv.reload()
+
+	return rv
+}
+
+// GetView returns the main flex view
+func (rv *ReflogView) GetView() tview.Primitive {
+	return rv.flex
+}
+
+// reload re-reads the reflog of the current ref and redraws it
+func (rv *ReflogView) reload() {
+	ref := rv.refs[rv.refIndex]
+	rv.listView.SetTitle(fmt.Sprintf("Reflog: %s (%d/%d)", ref, rv.refIndex+1, len(rv.refs)))
+	entries, err := git.ListReflog(rv.repoRoot, ref)
+	if err != nil {
+		rv.entries = nil
+		rv.listView.SetText("[red]" + tview.Escape(err.Error()))
+		return
+	}
+	rv.entries = entries
+	if rv.currentLine >= len(rv.entries) {
+		rv.currentLine = len(rv.entries) - 1
+	}
+	if rv.currentLine < 0 {
+		rv.currentLine = 0
+	}
+	rv.updateList()
+}
+
+// switchRef shows the reflog of the previous (-1) or next (1) ref
+func (rv *ReflogView) switchRef(direction int) {
+	rv.refIndex = (rv.refIndex + direction + len(rv.refs)) % len(rv.refs)
+	rv.currentLine = 0
+	rv.reload()
+}
+
+// updateList renders the entries with the current one highlighted
+func (rv *ReflogView) updateList() {
+	if len(rv.entries) == 0 {
+		rv.listView.SetText("No reflog entries")
+		return
+	}
+
+	var sb strings.Builder
+	for i, entry := range rv.entries {
+		if i == rv.currentLine {
+			sb.WriteString(fmt.Sprintf("[white:blue]%s  %s  %s: %s  (%s)[-:-]\n",
+				shortHash(entry.Hash), entry.Selector, tview.Escape(entry.Action), tview.Escape(entry.Message), entry.Age))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("[yellow]%s[-]  [aqua]%s[-]  [fuchsia]%s[-]: %s  [green](%s)[-]\n",
+			shortHash(entry.Hash), entry.Selector, tview.Escape(entry.Action), tview.Escape(entry.Message), entry.Age))
+	}
+	rv.listView.SetText(sb.String())
+
+	// Keep the current line visible
+	row, _ := rv.listView.GetScrollOffset()
+	_, _, _, height := rv.listView.GetInnerRect()
+	if rv.currentLine < row {
+		rv.listView.ScrollTo(rv.currentLine, 0)
+	} else if height > 0 && rv.currentLine >= row+height {
+		rv.listView.ScrollTo(rv.currentLine-height+1, 0)
+	}
+}
+
+// selected returns the entry under the cursor
+func (rv *ReflogView) selected() (git.ReflogEntry, bool) {
+	if rv.currentLine >= len(rv.entries) {
+		return git.ReflogEntry{}, false
+	}
+	return rv.entries[rv.currentLine], true
+}
+
+// setStatus shows a message in the status bar
+func (rv *ReflogView) setStatus(message, color string) {
+	rv.statusView.SetText(fmt.Sprintf("[%s]%s[-]", color, tview.Escape(message)))
+}
+
+// showEntryDetails shows the commit of the selected entry
+func (rv *ReflogView) showEntryDetails() {
+	entry, ok := rv.selected()
+	if !ok {
+		return
+	}
+
+	layout := newCommitDetailLayout(rv.app, rv.repoRoot, entry.Hash, getCommitFiles(rv.repoRoot, entry.Hash), rv.flex, rv.backToList)
+	layout.FileListView.SetTitle(entry.Selector + " " + shortHash(entry.Hash))
+
+	rv.flex.Clear()
+	rv.flex.AddItem(layout.View, 0, 1, true)
+	rv.app.SetFocus(layout.FileListView)
+}
+
+// backToList returns to the reflog from the commit details
+func (rv *ReflogView) backToList() {
+	rv.flex.Clear()
+	rv.flex.AddItem(rv.listView, 0, 1, true)
+	rv.flex.AddItem(rv.statusView, 3, 0, false)
+	rv.app.SetFocus(rv.listView)
+}
+
+// runAction runs a command on the commit of the selected entry and reports the result
+func (rv *ReflogView) runAction(entry git.ReflogEntry, action func() error, done string) {
+	if err := action(); err != nil {
+		rv.setStatus(err.Error(), "tomato")
+		return
+	}
+	rv.changed = true
+	rv.reload()
+	rv.setStatus(done, "forestgreen")
+}
+
+// checkoutSelected checks out the commit of the selected entry with a detached HEAD
+func (rv *ReflogView) checkoutSelected() {
+	entry, ok := rv.selected()
+	if !ok {
+		return
+	}
+	rv.runAction(entry, func() error {
+		return git.CheckoutDetached(rv.repoRoot, entry.Hash)
+	}, "Checked out "+entry.Selector+" ("+shortHash(entry.Hash)+")")
+}
+
+// promptBranch asks for a branch name and creates it at the selected entry
+func (rv *ReflogView) promptBranch() {
+	entry, ok := rv.selected()
+	if !ok {
+		return
+	}
+	showInputPrompt(rv.app, rv.flex, "New branch at "+entry.Selector+": ", "", rv.listView, func(name string) {
+		if err := git.CreateBranch(rv.repoRoot, name, entry.Hash); err != nil {
+			rv.setStatus(err.Error(), "tomato")
+			return
+		}
+		rv.refs = append(rv.refs, name)
+		rv.setStatus("Created branch "+name+" at "+shortHash(entry.Hash), "forestgreen")
+	})
+}
+
+// chooseReset asks how to reset HEAD to the selected entry
+func (rv *ReflogView) chooseReset() {
+	entry, ok := rv.selected()
+	if !ok {
+		return
+	}
+	subject := strings.TrimSpace(entry.Action + ": " + entry.Message)
+	showResetMenu(rv.app, rv.flex, rv.listView, entry.Hash, subject, func(mode git.ResetMode) {
+		rv.runAction(entry, func() error {
+			return resetTo(rv.repoRoot, entry.Hash, mode)
+		}, "Reset ("+mode.String()+") to "+entry.Selector+" ("+shortHash(entry.Hash)+")")
+	})
+}
+
+// setupKeyBindings configures keyboard navigation for the reflog
+func (rv *ReflogView) setupKeyBindings() {
+	rv.listView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			if rv.onExit != nil {
+				rv.onExit(rv.changed)
+			}
+			return nil
+		case tcell.KeyEnter:
+			rv.showEntryDetails()
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'j':
+			if rv.currentLine < len(rv.entries)-1 {
+				rv.currentLine++
+				rv.updateList()
+			}
+			return nil
+		case 'k':
+			if rv.currentLine > 0 {
+				rv.currentLine--
+				rv.updateList()
+			}
+			return nil
+		case '[':
+			rv.switchRef(-1)
+			return nil
+		case ']':
+			rv.switchRef(1)
+			return nil
+		case 'c':
+			rv.checkoutSelected()
+			return nil
+		case 'b':
+			rv.promptBranch()
+			return nil
+		case 'x':
+			rv.chooseReset()
+			return nil
+		case 'q':
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				os.Exit(0)
+			}()
+			rv.app.Stop()
+			return nil
+		}
+
+		return event
+	})
+}
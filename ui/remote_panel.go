@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+	"github.com/sukechannnn/giff/util"
+)
+
+// remotePanelHeight is the height of the panel showing git's progress
+const remotePanelHeight = 10
+
+// progressWriter shows streamed git output in a text view. A carriage return
+// rewrites the current line, the way a terminal shows git's progress counters.
+type progressWriter struct {
+	app     *tview.Application
+	view    *tview.TextView
+	mu      sync.Mutex
+	lines   []string
+	current string
+	pending bool // a carriage return was written: the next text replaces the line
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	for _, r := range string(p) {
+		switch r {
+		case '\r':
+			w.pending = true
+		case '\n':
+			w.lines = append(w.lines, w.current)
+			w.current = ""
+			w.pending = false
+		default:
+			if w.pending {
+				w.current = ""
+				w.pending = false
+			}
+			w.current += string(r)
+		}
+	}
+	text := w.text()
+	w.mu.Unlock()
+
+	w.app.QueueUpdateDraw(func() {
+		w.view.SetText(text)
+		w.view.ScrollToEnd()
+	})
+	return len(p), nil
+}
+
+// text returns everything written so far, escaped for the text view
+func (w *progressWriter) text() string {
+	lines := append(append([]string{}, w.lines...), w.current)
+	return tview.Escape(strings.Join(lines, "\n"))
+}
+
+// runRemoteCommand runs a fetch, pull or push in the background and streams
+// its output into a panel at the bottom of the main view. The panel closes
+// on success; on failure it stays with a readable error until Esc.
+func runRemoteCommand(ctx *FileListKeyContext, title, done string, run func(progress io.Writer) error) {
+	if ctx.remoteCommandRunning {
+		ctx.updateGlobalStatus("Wait for the running fetch, pull or push to finish", "yellow")
+		return
+	}
+	ctx.remoteCommandRunning = true
+	if ctx.remotePanel != nil {
+		ctx.mainFlex.RemoveItem(ctx.remotePanel)
+	}
+
+	panel := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	panel.SetBorder(true).SetTitle(" " + title + " ")
+	panel.SetTitleAlign(tview.AlignLeft)
+	panel.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+	ctx.remotePanel = panel
+	ctx.mainFlex.AddItem(panel, remotePanelHeight, 0, false)
+	ctx.updateGlobalStatus("Running "+title+"...", "yellow")
+
+	writer := &progressWriter{app: ctx.app, view: panel}
+	go func() {
+		err := run(writer)
+		ctx.app.QueueUpdateDraw(func() {
+			ctx.remoteCommandRunning = false
+			ctx.refreshFileList()
+			ctx.updateFileListView()
+			ctx.updateSelectedFileDiff()
+
+			if err == nil {
+				ctx.mainFlex.RemoveItem(panel)
+				ctx.updateGlobalStatus(done, "forestgreen")
+				return
+			}
+
+			// Keep the output and explain the failure below it
+			writer.mu.Lock()
+			output := strings.TrimSpace(writer.text())
+			writer.mu.Unlock()
+			if output != "" {
+				output += "\n"
+			}
+			panel.SetText(output + "[tomato]" + tview.Escape(err.Error()) + "[-]")
+			panel.ScrollToEnd()
+			panel.SetTitle(" " + title + " failed (Esc: close) ")
+			panel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEsc || event.Key() == tcell.KeyEnter || event.Rune() == 'q' {
+					ctx.mainFlex.RemoveItem(panel)
+					ctx.app.SetFocus(ctx.fileListView)
+					return nil
+				}
+				return event
+			})
+			// Another view may be open by now; the panel then closes with the next run
+			if ctx.app.GetFocus() == ctx.fileListView {
+				ctx.app.SetFocus(panel)
+			}
+			ctx.updateGlobalStatus(err.Error(), "tomato")
+		})
+	}()
+}
+
+// pullTitle describes the pull about to run, e.g. "git pull --rebase"
+func pullTitle(repoRoot string) string {
+	head, err := git.GetHeadStatus(repoRoot)
+	if err != nil || head.Branch == "" {
+		return "git pull"
+	}
+	return "git pull --" + git.PullMode(repoRoot, head.Branch)
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+)
+
+// showResetMenu asks how to reset HEAD to hash over mainView, confirms a hard
+// reset once more and calls onReset with the chosen mode. Focus goes back to
+// returnFocus.
+func showResetMenu(app *tview.Application, mainView, returnFocus tview.Primitive, hash, subject string, onReset func(mode git.ResetMode)) {
+	showModal := func(modal *tview.Modal) {
+		pages := tview.NewPages().
+			AddPage("main", mainView, true, true).
+			AddPage("modal", modal, true, true)
+		app.SetRoot(pages, true)
+	}
+	back := func() {
+		app.SetRoot(mainView, true)
+		app.SetFocus(returnFocus)
+	}
+
+	confirmHard := tview.NewModal().
+		SetText("Hard reset to " + shortHash(hash) + "?\nAll uncommitted changes to tracked files will be lost.").
+		AddButtons([]string{"Reset --hard", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			back()
+			if buttonLabel == "Reset --hard" {
+				onReset(git.ResetHard)
+			}
+		})
+
+	modal := tview.NewModal().
+		SetText("Reset HEAD to " + shortHash(hash) + " (" + tview.Escape(subject) + ")\n\n" +
+			"Soft: keep changes staged\nMixed: keep changes unstaged\nHard: discard all changes").
+		AddButtons([]string{"Soft", "Mixed", "Hard", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			back()
+			switch buttonLabel {
+			case "Soft":
+				onReset(git.ResetSoft)
+			case "Mixed":
+				onReset(git.ResetMixed)
+			case "Hard":
+				showModal(confirmHard)
+			}
+		})
+
+	showModal(modal)
+}
+
+// resetTo moves HEAD to hash. Soft and mixed resets only touch HEAD and the
+// index, so they are journaled and can be undone.
+func resetTo(repoRoot, hash string, mode git.ResetMode) error {
+	reset := func() error {
+		return git.Reset(repoRoot, hash, mode)
+	}
+	if mode == git.ResetHard {
+		return reset()
+	}
+	return journalAction(repoRoot, mode.String()+" reset to "+shortHash(hash), nil, reset)
+}
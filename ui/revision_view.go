@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+	"github.com/sukechannnn/giff/util"
+)
+
+var revisionViewKeyMessage = "j/k:move  Enter:switch  /:filter  e:fold  s:split  w:ws  y:yank  Y:copy path  q:quit"
+
+// RevisionView shows the comparison described by a revision range (read-only)
+func RevisionView(app *tview.Application, repoRoot string, revisionRange git.RevisionRange) (tview.Primitive, error) {
+	layout, _, err := newRevisionDiffLayout(app, repoRoot, revisionRange, revisionViewKeyMessage, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return layout.View, nil
+}
+
+// displayRevision abbreviates full commit hashes for titles
+func displayRevision(rev string) string {
+	if len(rev) >= 40 && strings.Trim(rev, "0123456789abcdef") == "" {
+		return shortHash(rev)
+	}
+	return rev
+}
+
+// displayRevisionRange is RevisionRange.String with abbreviated commit hashes
+func displayRevisionRange(r git.RevisionRange) string {
+	r.From = displayRevision(r.From)
+	r.To = displayRevision(r.To)
+	return r.String()
+}
+
+// revisionTarget names the right-hand side of a revision range
+func revisionTarget(r git.RevisionRange) string {
+	switch {
+	case r.To != "":
+		return displayRevision(r.To)
+	case r.Index:
+		return "index"
+	default:
+		return "working tree"
+	}
+}
+
+// newRevisionDiffLayout builds the read-only layout of the files that differ
+// in a revision range. It also returns the number of files.
+func newRevisionDiffLayout(app *tview.Application, repoRoot string, revisionRange git.RevisionRange, statusMessage string, mainView tview.Primitive, onEsc func()) (*ReadOnlyDiffLayout, int, error) {
+	files, err := git.GetRevisionChangedFiles(revisionRange, repoRoot)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]FileEntry, 0, len(files))
+	for _, f := range files {
+		entries = append(entries, FileEntry{
+			Path:         f.Path,
+			StageStatus:  "commit",
+			ChangeStatus: f.ChangeStatus,
+		})
+	}
+
+	layout := NewReadOnlyDiffLayout(ReadOnlyDiffLayoutParams{
+		App:           app,
+		RepoRoot:      repoRoot,
+		Files:         entries,
+		FileListTitle: "Changes in " + displayRevisionRange(revisionRange),
+		DiffViewTitle: displayRevision(revisionRange.From) + " → " + revisionTarget(revisionRange),
+		StatusMessage: statusMessage,
+		MainView:      mainView,
+		LoadDiff: func(filePath string, ignoreWhitespace bool) string {
+			diffText, err := git.GetRevisionDiffWithOptions(filePath, repoRoot, revisionRange, ignoreWhitespace)
+			if err != nil {
+				return ""
+			}
+			return diffText
+		},
+		OnEsc: onEsc,
+	})
+
+	return layout, len(entries), nil
+}
+
+var baseViewKeyMessage = "C:committed S:staged U:unstaged  j/k:move  Enter:switch  /:filter  e:fold  s:split  w:ws  q:quit"
+
+// BaseView shows everything the current branch changes since its merge-base with base,
+// including staged, unstaged and untracked changes (read-only)
+func BaseView(app *tview.Application, repoRoot string, base string) (tview.Primitive, error) {
+	mergeBase, err := git.GetMergeBase(base, repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := git.GetBaseChanges(mergeBase, repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]FileEntry, 0, len(changes))
+	untracked := make(map[string]bool)
+	for _, c := range changes {
+		if c.ChangeStatus == "untracked" {
+			untracked[c.Path] = true
+		}
+		entries = append(entries, FileEntry{
+			Path:         c.Path,
+			StageStatus:  "commit",
+			ChangeStatus: c.ChangeStatus,
+			Badge:        formatBaseLayers(c),
+		})
+	}
+
+	revisionRange := git.RevisionRange{From: mergeBase}
+	layout := NewReadOnlyDiffLayout(ReadOnlyDiffLayoutParams{
+		App:           app,
+		RepoRoot:      repoRoot,
+		Files:         entries,
+		FileListTitle: "Changes since " + base,
+		DiffViewTitle: fmt.Sprintf("merge-base %s → working tree", shortHash(mergeBase)),
+		StatusMessage: baseViewKeyMessage,
+		LoadDiff: func(filePath string, ignoreWhitespace bool) string {
+			if untracked[filePath] {
+				content, err := util.ReadFileContent(filePath, repoRoot)
+				if err != nil {
+					return ""
+				}
+				return util.FormatAsAddedLines(content, filePath)
+			}
+			diffText, err := git.GetRevisionDiffWithOptions(filePath, repoRoot, revisionRange, ignoreWhitespace)
+			if err != nil {
+				return ""
+			}
+			return diffText
+		},
+	})
+
+	return layout.View, nil
+}
+
+// formatBaseLayers returns a fixed-width marker of the layers a change lives in
+func formatBaseLayers(c git.BaseChange) string {
+	marks := []string{"-", "-", "-"}
+	if c.Committed {
+		marks[0] = "C"
+	}
+	if c.Staged {
+		marks[1] = "S"
+	}
+	if c.Unstaged {
+		marks[2] = "U"
+	}
+	return "[" + strings.Join(marks, " ") + "]"
+}
+
+// shortHash abbreviates a full commit hash for display
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
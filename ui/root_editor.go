@@ -20,8 +20,9 @@ var preferUnstagedSection bool = false
 
 // globalStatusView defined globally
 var globalStatusView *tview.TextView
-var fileListKeyMessage = "a:stage  A:stage file  d:discard  C-a:stage all  C-k:commit  C-j:amend  H/L:dir  s:split  w:ws  /:filter  v:editor  c:code  C-l:log  t:terminal  Y:copy  C-e/C-y:scroll  Enter:switch  q:quit"
-var diffViewKeyMessage = "a:stage lines  A:stage file  V:select  g/G:top/end  /:search  e:fold  s:split  w:ws  y:yank  Y:copy path  C-e/C-y:scroll  Esc:back  q:quit"
+var fileListKeyMessage = "a:stage  A:stage file  d:discard  D:discarded  b:branches  h:history  z/Z:stash/stashes  R:reflog  C/S/X:continue/skip/abort  f/p/P:fetch/pull/push  u/C-r:undo/redo  C-a:stage all  C-k:commit  C-j:amend  H/L:dir  s:split  w:ws  /:filter  v:editor  c:code  C-l:log  t:terminal  Y:copy  C-e/C-y:scroll  Enter:switch  q:quit"
+var diffViewKeyMessage = "a:stage lines  S:stage hunk  A:stage file  d:discard hunk/lines  ]c/[c:next/prev hunk  u/C-r:undo/redo  V:select  g/G:top/end  /:search  e:fold  s:split  w:ws  y:yank  Y:copy path  B:blame  h:history  C-e/C-y:scroll  Esc:back  q:quit"
+var conflictViewKeyMessage = "<:take ours  >:take theirs  b:take both  A:mark resolved  j/k:move  g/G:top/end  /:search  e:fold  Esc:back  q:quit"
 
 // restoreStatusFunc is called to restore the default status message (set by SetupRootEditor)
 var restoreStatusFunc func()
@@ -53,6 +54,8 @@ func updateCurrentDiffText(filePath string, status string, repoRoot string, curr
 	switch status {
 	case "staged":
 		diffText, err = git.GetStagedDiffWithOptions(filePath, repoRoot, ignoreWhitespace)
+	case "conflicted":
+		diffText, err = git.GetConflictDiff(filePath, repoRoot)
 	case "untracked":
 		content, readErr := util.ReadFileContent(filePath, repoRoot)
 		if readErr != nil {
@@ -82,8 +85,11 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 	// Commit-related state
 	var isCommitMode bool = false
 	var isAmendMode bool = false
+	var rewordHash string = "" // commit whose message is being reworded from the log view
 	var commitMessage string = ""
 	var focusBeforeCommit tview.Primitive = nil // focus position before commit mode
+	commitOptions := git.CommitOptions{GPGSign: git.GPGSignByDefault(repoRoot)}
+	var commitLabel string = "Commit Message" // title of the commit area without the options
 
 	// Terminal command mode state
 	var isTerminalMode bool = false
@@ -120,6 +126,9 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 	// Function to update the status bar title
 	updateStatusTitle := func() {
 		var titleParts []string
+		if banner := operationBanner(git.CurrentOperation(repoRoot)); banner != "" {
+			titleParts = append(titleParts, banner)
+		}
 		if enableAutoRefresh {
 			titleParts = append(titleParts, "Watch: on")
 		}
@@ -136,6 +145,8 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 	restoreStatusFunc = func() {
 		if leftPaneFocused {
 			globalStatusView.SetText(fileListKeyMessage)
+		} else if currentStatus == "conflicted" {
+			globalStatusView.SetText(conflictViewKeyMessage)
 		} else {
 			globalStatusView.SetText(diffViewKeyMessage)
 		}
@@ -149,6 +160,15 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 	globalStatusView.SetBorder(true)
 	globalStatusView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
 
+	// One-line summary of the branch, HEAD and changes above the status bar
+	statusHeaderView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	statusHeaderView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+	updateStatusHeader := func() {
+		statusHeaderView.SetText(statusHeaderText(repoRoot))
+	}
+
 	// Create flex layout (vertical split, then horizontal split below)
 	mainFlex := tview.NewFlex().SetDirection(tview.FlexRow)
 
@@ -230,6 +250,27 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 	commitTextArea.SetTitleAlign(tview.AlignLeft)
 	commitTextArea.SetTitleColor(tcell.ColorWhite)
 
+	// Output of a rejected commit (hooks), shown above the message area
+	commitOutputView := newCommitOutputView(func() {
+		app.SetFocus(commitTextArea)
+	})
+	setCommitTitle := func(label string) {
+		commitLabel = label
+		commitTextArea.SetTitle(commitAreaTitle(label, commitOptions))
+	}
+	hideCommitOutput := func() {
+		mainFlex.RemoveItem(commitOutputView)
+	}
+	showCommitOutput := func(text string) {
+		commitOutputView.SetText(text)
+		commitOutputView.ScrollToBeginning()
+		hideCommitOutput()
+		mainFlex.RemoveItem(commitTextArea)
+		mainFlex.AddItem(commitOutputView, commitOutputHeight, 0, false)
+		mainFlex.AddItem(commitTextArea, 7, 0, true)
+		app.SetFocus(commitOutputView)
+	}
+
 	// Terminal command input
 	terminalInput := tview.NewInputField().
 		SetLabel("[giff] $ ").
@@ -336,6 +377,9 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 			*modifiedFilesPtr = newModified
 			*untrackedFilesPtr = newUntracked
 		}
+		// A merge, rebase, etc. may have started or finished
+		updateStatusTitle()
+		updateStatusHeader()
 	}
 
 	// Function to update the diff of the selected file
@@ -439,6 +483,34 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 	// Set initial message
 	globalStatusView.SetText(fileListKeyMessage)
 	updateStatusTitle()
+	updateStatusHeader()
+
+	// Reword callback: edits the message of a commit picked in the log view
+	startRewordFunc := func(hash string) {
+		if isCommitMode {
+			updateGlobalStatus("Finish or cancel the commit message first", "tomato")
+			app.SetFocus(commitTextArea)
+			return
+		}
+		// Earlier actions in the log view may have changed the files
+		refreshFileList()
+		updateFileListView()
+		updateSelectedFileDiff()
+
+		message, err := git.GetCommitMessage(repoRoot, hash)
+		if err != nil {
+			updateGlobalStatus(err.Error(), "tomato")
+			return
+		}
+		focusBeforeCommit = fileListView
+		isCommitMode = true
+		isAmendMode = false
+		rewordHash = hash
+		commitTextArea.SetTitle("Commit Message (Reword " + shortHash(hash) + ")")
+		commitTextArea.SetText(message, false)
+		mainFlex.AddItem(commitTextArea, 7, 0, true)
+		app.SetFocus(commitTextArea)
+	}
 
 	// Terminal open callback (shared between diff view and file list)
 	openTerminalFunc := func() {
@@ -472,6 +544,7 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 		unifiedViewFlex: unifiedViewFlex,
 		contentFlex:     contentFlex,
 		app:             app,
+		mainView:        mainFlex,
 
 		// State
 		cursorY:               &cursorY,
@@ -546,6 +619,7 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 		contentFlex:     contentFlex,
 		app:             app,
 		mainView:        mainFlex, // add reference to main view
+		mainFlex:        mainFlex,
 
 		// State
 		currentSelection:  &currentSelection,
@@ -586,7 +660,9 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 				globalStatusView.SetText(text)
 			}
 		},
+		onUpdate:     onUpdate,
 		openTerminal: openTerminalFunc,
+		startReword:  startRewordFunc,
 	}
 	SetupFileListKeyBindings(fileListKeyContext)
 
@@ -596,6 +672,7 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 
 		// Variables to cache previous file list
 		var lastStagedFiles, lastModifiedFiles, lastUntrackedFiles []git.FileInfo
+		var lastBanner string
 
 		// Function to determine if file list has changed
 		hasFileListChanged := func(newStaged, newModified, newUntracked []git.FileInfo) bool {
@@ -637,6 +714,15 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 			for {
 				select {
 				case <-ticker.C:
+					// Show or clear the banner when an operation moved on outside giff
+					if banner := operationBanner(git.CurrentOperation(repoRoot)); banner != lastBanner {
+						lastBanner = banner
+						app.QueueUpdateDraw(func() {
+							updateStatusTitle()
+							updateStatusHeader()
+						})
+					}
+
 					// Get new file list
 					newStaged, newModified, newUntracked, err := git.GetChangedFiles(repoRoot)
 					if err != nil {
@@ -669,6 +755,8 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 					}
 
 					app.QueueUpdateDraw(func() {
+						// The header is recomputed only when the changes moved (or on an explicit refresh)
+						updateStatusHeader()
 
 						// Save currently selected file and status
 						var currentlySelectedFile string
@@ -787,10 +875,12 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 	exitCommitMode := func() {
 		isCommitMode = false
 		isAmendMode = false
+		rewordHash = ""
 		leftPaneFocused = true
 		restoreStatusFunc()
 		commitTextArea.SetText("", false)
 		commitTextArea.SetTitle("Commit Message")
+		hideCommitOutput()
 		mainFlex.RemoveItem(commitTextArea)
 		app.SetFocus(fileListView)
 	}
@@ -867,19 +957,53 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 					return nil
 				}
 
-				var err error
+				if rewordHash != "" {
+					// Rewriting history is not journaled: undo cannot restore the rebased files
+					if err := git.RewordCommit(repoRoot, rewordHash, commitMessage); err != nil {
+						if !git.RebaseInProgress(repoRoot) {
+							updateGlobalStatus("Failed to reword commit: "+err.Error(), "tomato")
+							return nil
+						}
+						updateGlobalStatus("Rebase stopped on conflicts while rewording "+shortHash(rewordHash)+
+							": resolve them, then press C to continue", "tomato")
+					} else {
+						updateGlobalStatus("Reworded "+shortHash(rewordHash), "forestgreen")
+					}
+					refreshFileList()
+					updateFileListView()
+					updateSelectedFileDiff()
+					exitCommitMode()
+					return nil
+				}
+
+				description := "commit"
 				if isAmendMode {
-					err = git.CommitAmend(commitMessage, repoRoot)
+					description = "amend commit"
+				}
+				options := commitOptions
+				options.Amend = isAmendMode
+				commit := func() error {
+					return journalAction(repoRoot, description, nil, func() error {
+						return git.Commit(commitMessage, repoRoot, options)
+					})
+				}
+				var err error
+				if options.GPGSign && git.SigningNeedsTerminal(repoRoot) {
+					// pinentry asks for the passphrase on the terminal
+					app.Suspend(func() {
+						err = commit()
+					})
 				} else {
-					err = git.Commit(commitMessage, repoRoot)
+					err = commit()
 				}
 				if err != nil {
 					if isAmendMode {
-						updateGlobalStatus("Failed to amend commit: "+err.Error(), "tomato")
+						updateGlobalStatus("Failed to amend commit: see the output above", "tomato")
 					} else {
-						updateGlobalStatus("Failed to commit: "+err.Error(), "tomato")
+						updateGlobalStatus("Failed to commit: see the output above", "tomato")
 					}
 					// On error, don't exit commit mode so user can retry with message preserved
+					showCommitOutput(commitRejectionText(err))
 					return nil
 				}
 
@@ -907,6 +1031,14 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 			}
 			// Normal Enter is handled as newline
 			return event
+		case tcell.KeyRune:
+			// Option+S/N/G toggle signoff, no-verify and signing (not for a reword)
+			if event.Modifiers()&tcell.ModAlt != 0 && rewordHash == "" {
+				if toggleCommitOption(&commitOptions, event.Rune()) {
+					setCommitTitle(commitLabel)
+					return nil
+				}
+			}
 		case tcell.KeyCtrlL:
 			app.SetFocus(fileListView)
 			return nil
@@ -924,7 +1056,8 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 	})
 
 	// Add status view and content to mainFlex
-	mainFlex.AddItem(globalStatusView, 3, 0, false).
+	mainFlex.AddItem(statusHeaderView, 1, 0, false).
+		AddItem(globalStatusView, 3, 0, false).
 		AddItem(contentFlex, 0, 1, true)
 
 	mainFlex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
@@ -946,6 +1079,13 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 				}
 				isCommitMode = true
 				isAmendMode = false
+				setCommitTitle("Commit Message")
+				// Start from commit.template when one is configured
+				if template, err := git.GetCommitTemplate(repoRoot); err != nil {
+					updateGlobalStatus(err.Error(), "tomato")
+				} else {
+					commitTextArea.SetText(template, false)
+				}
 				mainFlex.AddItem(commitTextArea, 7, 0, true) // Height set to 7 to support multi-line input
 				app.SetFocus(commitTextArea)
 			} else {
@@ -974,7 +1114,7 @@ func RootEditor(app *tview.Application, stagedFiles, modifiedFiles, untrackedFil
 				}
 				isCommitMode = true
 				isAmendMode = true
-				commitTextArea.SetTitle("Commit Message (Amend)")
+				setCommitTitle("Commit Message (Amend)")
 				commitTextArea.SetText(lastCommitMsg, false)
 				mainFlex.AddItem(commitTextArea, 7, 0, true)
 				app.SetFocus(commitTextArea)
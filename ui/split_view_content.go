@@ -15,6 +15,7 @@ type SplitViewContent struct {
 	AfterLines     []string
 	BeforeLineNums []string
 	AfterLineNums  []string
+	RowLines       [][]int // display lines (as numbered by createLineNumberMapping) shown on each row
 }
 
 // generateSplitViewContent generates content for split view from diff text
@@ -206,6 +207,15 @@ func generateSplitViewContent(diffText string, oldLineMap, newLineMap map[int]in
 				content.AfterLines = append(content.AfterLines, afterLine)
 				content.BeforeLineNums = append(content.BeforeLineNums, beforeLineNum)
 				content.AfterLineNums = append(content.AfterLineNums, afterLineNum)
+
+				var rowLines []int
+				if k < len(deletions) {
+					rowLines = append(rowLines, deletions[k].displayIndex)
+				}
+				if k < len(additions) {
+					rowLines = append(rowLines, additions[k].displayIndex)
+				}
+				content.RowLines = append(content.RowLines, rowLines)
 			}
 
 			i = j
@@ -220,6 +230,7 @@ func generateSplitViewContent(diffText string, oldLineMap, newLineMap map[int]in
 			} else {
 				content.AfterLineNums = append(content.AfterLineNums, strings.Repeat(" ", maxDigits))
 			}
+			content.RowLines = append(content.RowLines, []int{line.displayIndex})
 			i++
 			codeIdx++
 		case " ":
@@ -238,6 +249,7 @@ func generateSplitViewContent(diffText string, oldLineMap, newLineMap map[int]in
 			} else {
 				content.AfterLineNums = append(content.AfterLineNums, strings.Repeat(" ", maxDigits))
 			}
+			content.RowLines = append(content.RowLines, []int{line.displayIndex})
 			i++
 			codeIdx++
 		default:
@@ -256,6 +268,7 @@ func generateSplitViewContent(diffText string, oldLineMap, newLineMap map[int]in
 			} else {
 				content.AfterLineNums = append(content.AfterLineNums, strings.Repeat(" ", maxDigits))
 			}
+			content.RowLines = append(content.RowLines, []int{line.displayIndex})
 			i++
 			codeIdx++
 		}
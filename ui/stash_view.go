@@ -0,0 +1,337 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+	"github.com/sukechannnn/giff/util"
+)
+
+var stashViewKeyMessage = "j/k:move  Enter:show  a:apply  p:pop  d:drop  b:branch  Esc:back  q:quit"
+
+// StashView lists the stashes and applies, pops, drops or branches from them
+type StashView struct {
+	app         *tview.Application
+	listView    *tview.TextView
+	statusView  *tview.TextView
+	flex        *tview.Flex
+	repoRoot    string
+	stashes     []git.StashEntry
+	currentLine int
+	changed     bool // the working tree or index was modified
+	onExit      func(changed bool)
+}
+
+// NewStashView creates a view of the stash list
+func NewStashView(app *tview.Application, repoRoot string, onExit func(changed bool)) *StashView {
+	listView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	listView.SetBorder(true).SetTitle("Stashes")
+	listView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	statusView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false).
+		SetText(stashViewKeyMessage)
+	statusView.SetBorder(true)
+	statusView.SetBackgroundColor(util.BackgroundColor.ToTcellColor())
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(listView, 0, 1, true).
+		AddItem(statusView, 3, 0, false)
+
+	sv := &StashView{
+		app:        app,
+		listView:   listView,
+		statusView: statusView,
+		flex:       flex,
+		repoRoot:   repoRoot,
+		onExit:     onExit,
+	}
+
+	sv.setupKeyBindings()
+	sv.reload()
+
+	return sv
+}
+
+// GetView returns the main flex view
+func (sv *StashView) GetView() tview.Primitive {
+	return sv.flex
+}
+
+// reload re-reads the stash list and redraws it
+func (sv *StashView) reload() {
+	stashes, err := git.ListStashes(sv.repoRoot)
+	if err != nil {
+		sv.listView.SetText("[red]Failed to load stashes: " + tview.Escape(err.Error()))
+		return
+	}
+	sv.stashes = stashes
+	if sv.currentLine >= len(sv.stashes) {
+		sv.currentLine = len(sv.stashes) - 1
+	}
+	if sv.currentLine < 0 {
+		sv.currentLine = 0
+	}
+	sv.updateList()
+}
+
+// updateList renders the stashes with the current one highlighted
+func (sv *StashView) updateList() {
+	if len(sv.stashes) == 0 {
+		sv.listView.SetText("No stashes")
+		return
+	}
+
+	var sb strings.Builder
+	for i, stash := range sv.stashes {
+		line := fmt.Sprintf("[yellow]%s[-]  %s  [green](%s)[-]", stash.Ref, tview.Escape(stash.Message), stash.Age)
+		if i == sv.currentLine {
+			line = fmt.Sprintf("%s  %s  (%s)", stash.Ref, tview.Escape(stash.Message), stash.Age)
+			sb.WriteString("[white:blue]" + line + "[-:-]\n")
+		} else {
+			sb.WriteString(line + "\n")
+		}
+	}
+	sv.listView.SetText(sb.String())
+}
+
+// selected returns the stash under the cursor
+func (sv *StashView) selected() (git.StashEntry, bool) {
+	if sv.currentLine >= len(sv.stashes) {
+		return git.StashEntry{}, false
+	}
+	return sv.stashes[sv.currentLine], true
+}
+
+// setStatus shows a message in the status bar
+func (sv *StashView) setStatus(message, color string) {
+	sv.statusView.SetText(fmt.Sprintf("[%s]%s[-]", color, tview.Escape(message)))
+}
+
+// showStashDetails shows the files and diffs of the selected stash
+func (sv *StashView) showStashDetails() {
+	stash, ok := sv.selected()
+	if !ok {
+		return
+	}
+
+	files, err := git.GetStashFiles(stash, sv.repoRoot)
+	if err != nil {
+		sv.setStatus(err.Error(), "tomato")
+		return
+	}
+	entries := make([]FileEntry, 0, len(files))
+	for _, f := range files {
+		entries = append(entries, FileEntry{
+			Path:         f.Path,
+			StageStatus:  "commit",
+			ChangeStatus: f.ChangeStatus,
+		})
+	}
+
+	layout := NewReadOnlyDiffLayout(ReadOnlyDiffLayoutParams{
+		App:           sv.app,
+		RepoRoot:      sv.repoRoot,
+		Files:         entries,
+		FileListTitle: stash.Ref,
+		DiffViewTitle: stash.Message,
+		StatusMessage: readOnlyDiffKeyMessage,
+		MainView:      sv.flex,
+		LoadDiff: func(filePath string, ignoreWhitespace bool) string {
+			diffText, err := git.GetStashDiffWithOptions(filePath, sv.repoRoot, stash, ignoreWhitespace)
+			if err != nil {
+				return ""
+			}
+			return diffText
+		},
+		OnEsc: sv.backToList,
+	})
+
+	sv.flex.Clear()
+	sv.flex.AddItem(layout.View, 0, 1, true)
+	sv.app.SetFocus(layout.FileListView)
+}
+
+// backToList returns to the stash list from the stash details
+func (sv *StashView) backToList() {
+	sv.flex.Clear()
+	sv.flex.AddItem(sv.listView, 0, 1, true)
+	sv.flex.AddItem(sv.statusView, 3, 0, false)
+	sv.app.SetFocus(sv.listView)
+}
+
+// runAction runs a stash command on the selected stash and reports the result
+func (sv *StashView) runAction(action func(string, git.StashEntry) error, done string) {
+	stash, ok := sv.selected()
+	if !ok {
+		return
+	}
+	if err := action(sv.repoRoot, stash); err != nil {
+		sv.setStatus(err.Error(), "tomato")
+		sv.changed = true // a failed apply can still leave conflicts behind
+		return
+	}
+	sv.changed = true
+	sv.reload()
+	sv.setStatus(done+" "+stash.Ref, "forestgreen")
+}
+
+// confirmDrop asks for confirmation before dropping the selected stash
+func (sv *StashView) confirmDrop() {
+	stash, ok := sv.selected()
+	if !ok {
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Drop %s (%s)?", stash.Ref, tview.Escape(stash.Message))).
+		AddButtons([]string{"Drop", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			sv.app.SetRoot(sv.flex, true)
+			sv.app.SetFocus(sv.listView)
+			if buttonLabel != "Drop" {
+				return
+			}
+			if err := git.DropStash(sv.repoRoot, stash); err != nil {
+				sv.setStatus(err.Error(), "tomato")
+				return
+			}
+			sv.reload()
+			sv.setStatus("Dropped "+stash.Ref, "forestgreen")
+		})
+
+	pages := tview.NewPages().
+		AddPage("main", sv.flex, true, true).
+		AddPage("modal", modal, true, true)
+	sv.app.SetRoot(pages, true)
+}
+
+// promptBranch asks for a branch name and creates it from the selected stash
+func (sv *StashView) promptBranch() {
+	stash, ok := sv.selected()
+	if !ok {
+		return
+	}
+
+	showInputPrompt(sv.app, sv.flex, "Branch name: ", "", sv.listView, func(branch string) {
+		if err := git.BranchFromStash(sv.repoRoot, branch, stash); err != nil {
+			sv.setStatus(err.Error(), "tomato")
+			return
+		}
+		sv.changed = true
+		sv.reload()
+		sv.setStatus("Switched to new branch "+branch+" with "+stash.Ref, "forestgreen")
+	})
+}
+
+// setupKeyBindings configures keyboard navigation for the stash list
+func (sv *StashView) setupKeyBindings() {
+	sv.listView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			if sv.onExit != nil {
+				sv.onExit(sv.changed)
+			}
+			return nil
+		case tcell.KeyEnter:
+			sv.showStashDetails()
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'j':
+			if sv.currentLine < len(sv.stashes)-1 {
+				sv.currentLine++
+				sv.updateList()
+			}
+			return nil
+		case 'k':
+			if sv.currentLine > 0 {
+				sv.currentLine--
+				sv.updateList()
+			}
+			return nil
+		case 'a':
+			sv.runAction(git.ApplyStash, "Applied")
+			return nil
+		case 'p':
+			sv.runAction(git.PopStash, "Popped")
+			return nil
+		case 'd':
+			sv.confirmDrop()
+			return nil
+		case 'b':
+			sv.promptBranch()
+			return nil
+		case 'q':
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				os.Exit(0)
+			}()
+			sv.app.Stop()
+			return nil
+		}
+
+		return event
+	})
+}
+
+// showStashMenu asks which changes to stash: everything, the staged changes
+// or the file/directory selected in the file list
+func showStashMenu(ctx *FileListKeyContext) {
+	var selectedPath string
+	if *ctx.currentSelection >= 0 && *ctx.currentSelection < len(*ctx.fileList) {
+		selectedPath = (*ctx.fileList)[*ctx.currentSelection].Path
+	}
+
+	buttons := []string{"All", "Staged"}
+	text := "Stash which changes?"
+	if selectedPath != "" {
+		buttons = append(buttons, "Selected")
+		text = "Stash which changes?\n(Selected: " + selectedPath + ")"
+	}
+	buttons = append(buttons, "Cancel")
+
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons(buttons).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			// Return to the original view
+			ctx.app.SetRoot(ctx.mainView, true)
+			ctx.app.SetFocus(ctx.fileListView)
+
+			var err error
+			switch buttonLabel {
+			case "All":
+				err = git.StashPush(ctx.repoRoot, git.StashAll, "")
+			case "Staged":
+				err = git.StashPush(ctx.repoRoot, git.StashStaged, "")
+			case "Selected":
+				err = git.StashPush(ctx.repoRoot, git.StashPaths, "", selectedPath)
+			default:
+				return
+			}
+			if err != nil {
+				ctx.updateGlobalStatus(err.Error(), "tomato")
+				return
+			}
+			ctx.refreshFileList()
+			ctx.updateFileListView()
+			ctx.updateSelectedFileDiff()
+			ctx.updateGlobalStatus("Changes stashed (Z to view stashes)", "forestgreen")
+		})
+
+	// Display mainView fullscreen with modal overlay
+	pages := tview.NewPages().
+		AddPage("main", ctx.mainView, true, true).
+		AddPage("modal", modal, true, true)
+	ctx.app.SetRoot(pages, true)
+}
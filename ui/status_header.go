@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/sukechannnn/giff/git"
+)
+
+// statusHeaderText renders the one-line summary above the status bar: the
+// branch and its upstream, the HEAD commit and the size of the changes
+func statusHeaderText(repoRoot string) string {
+	head, err := git.GetHeadStatus(repoRoot)
+	if err != nil {
+		return "[tomato]" + tview.Escape(err.Error()) + "[-]"
+	}
+
+	var parts []string
+	parts = append(parts, formatHeadBranch(head))
+	if head.Hash == "" {
+		parts = append(parts, "[gray]no commits yet[-]")
+	} else {
+		parts = append(parts, "[yellow]"+shortHash(head.Hash)+"[-] "+tview.Escape(head.Subject))
+	}
+
+	if summary, err := git.GetWorkingTreeSummary(repoRoot); err == nil {
+		parts = append(parts, fmt.Sprintf("Staged %s  Unstaged %s  Untracked %d",
+			formatChangeCount(summary.Staged),
+			formatChangeCount(summary.Unstaged),
+			summary.Untracked))
+	}
+	return " " + strings.Join(parts, "  │  ")
+}
+
+// formatHeadBranch renders the branch (or detached HEAD) with its upstream
+// and the ahead/behind counts
+func formatHeadBranch(head git.HeadStatus) string {
+	if head.Branch == "" {
+		return "[tomato]HEAD (detached)[-]"
+	}
+	text := "[aqua]" + tview.Escape(head.Branch) + "[-]"
+	switch {
+	case head.Upstream == "":
+		text += " [gray](no upstream)[-]"
+	case head.UpstreamGone:
+		text += " → " + tview.Escape(head.Upstream) + " [tomato](gone)[-]"
+	default:
+		text += " → " + tview.Escape(head.Upstream)
+		if head.Ahead == 0 && head.Behind == 0 {
+			text += " [gray]up to date[-]"
+		}
+		if head.Ahead > 0 {
+			text += fmt.Sprintf(" [green]↑%d[-]", head.Ahead)
+		}
+		if head.Behind > 0 {
+			text += fmt.Sprintf(" [tomato]↓%d[-]", head.Behind)
+		}
+	}
+	return text
+}
+
+// formatChangeCount renders "<files> +<added> -<deleted>"
+func formatChangeCount(count git.ChangeCount) string {
+	return fmt.Sprintf("%d [green]+%d[-] [red]-%d[-]", count.Files, count.Added, count.Deleted)
+}
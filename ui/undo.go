@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"github.com/sukechannnn/giff/git"
+)
+
+// undoJournal keeps the undo history for the session (survives RootEditor rebuilds)
+var undoJournal = git.NewJournal()
+
+// journalAction runs an index-mutating action and records it for undo.
+// paths lists working tree files the action may change (e.g. discarded files).
+func journalAction(repoRoot, description string, paths []string, action func() error) error {
+	before, snapshotErr := git.TakeSnapshot(repoRoot, paths...)
+	if err := action(); err != nil {
+		return err
+	}
+	if snapshotErr != nil {
+		// The state before the action is unknown (e.g. unmerged index), so it cannot be undone
+		return nil
+	}
+	after, err := git.TakeSnapshot(repoRoot, before.FilePaths()...)
+	if err == nil {
+		undoJournal.Record(description, before, after)
+	}
+	return nil
+}
+
+// undoLastAction undoes the last journal entry, refreshes the view and reports the result
+func undoLastAction(repoRoot string, refresh func()) {
+	entry, err := undoJournal.Undo(repoRoot)
+	if err != nil {
+		updateGlobalStatus("Cannot undo: "+err.Error(), "tomato")
+		return
+	}
+	if refresh != nil {
+		refresh()
+	}
+	updateGlobalStatus("Undid: "+entry.Description, "forestgreen")
+}
+
+// redoLastAction re-applies the last undone journal entry, refreshes the view and reports the result
+func redoLastAction(repoRoot string, refresh func()) {
+	entry, err := undoJournal.Redo(repoRoot)
+	if err != nil {
+		updateGlobalStatus("Cannot redo: "+err.Error(), "tomato")
+		return
+	}
+	if refresh != nil {
+		refresh()
+	}
+	updateGlobalStatus("Redid: "+entry.Description, "forestgreen")
+}
@@ -16,6 +16,29 @@ const (
 	PlaceholderColor         = ColorColde("#808080")
 )
 
+// GraphLaneColors are the colors of the lanes in the commit graph
+var GraphLaneColors = []ColorColde{
+	"#8BE9FD", // cyan
+	"#50FA7B", // green
+	"#FFB86C", // orange
+	"#FF79C6", // pink
+	"#BD93F9", // purple
+	"#F1FA8C", // yellow
+	"#FF5555", // red
+}
+
+// Colors of a commit line in the git log
+const (
+	LogHashColor         = ColorColde("#F1FA8C")
+	LogDateColor         = ColorColde("#50FA7B")
+	LogAuthorColor       = ColorColde("#BD93F9")
+	LogRefHeadColor      = ColorColde("#8BE9FD") // HEAD and the branch it points to
+	LogRefTagColor       = ColorColde("#F1FA8C")
+	LogRefBranchColor    = ColorColde("#50FA7B")
+	LogRefRemoteColor    = ColorColde("#FF5555") // remote-tracking branches
+	LogRefDelimiterColor = ColorColde("#F1FA8C") // parentheses and commas around the refs
+)
+
 func (c ColorColde) hex() string {
 	return string(c)[1:]
 }